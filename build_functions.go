@@ -1,25 +1,81 @@
 package errors
 
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// NewBuilder returns an empty builder, stamped with the current time like the
+// builders returned by the other functions of the package, to start from when
+// the fields are only known one by one, e.g. when decoding an error.
+func NewBuilder() ErrorBuilder {
+	return newBuilder()
+}
+
+// New returns an error whose message is the given text. The error has no cause.
 func New(message string) error {
 	return newBuilder().Error(message)
 }
 
+// Newf is the formatted sibling of New: the formatted text becomes the message
+// and the error has no cause. Use Errorf to keep the formatted error as cause.
+func Newf(format string, args ...any) error {
+	return newBuilder().Error(fmt.Sprintf(format, args...))
+}
+
+// Wrap adds a layer with a new stack trace on top of err, even if err already
+// is an *Error. Use WrapOnce in pass-through layers that add no information.
 func Wrap(err error) error {
 	return newBuilder().Wrap(err)
 }
 
+// WrapOnce wraps err like Wrap, unless err already holds an *Error with a stack
+// trace, in which case err is returned unchanged. This keeps chains shallow when
+// an error flows through layers that have nothing to add.
+func WrapOnce(err error) error {
+	var e *Error
+	if errors.As(err, &e) && len(e.stackTrace) > 0 {
+		return err
+	}
+	return newBuilder().Wrap(err)
+}
+
 func Wrapf(err error, format string, args ...any) error {
 	return newBuilder().Wrapf(err, format, args...)
 }
 
+// Errorf returns an error whose cause is fmt.Errorf(format, args...), so that
+// errors wrapped with %w can be matched with Is.
 func Errorf(format string, args ...any) error {
 	return newBuilder().Errorf(format, args...)
 }
 
+func WithCauseChain(root error) error {
+	return newBuilder().WithCauseChain(root)
+}
+
 func Join(errs ...error) error {
 	return newBuilder().Join(errs...)
 }
 
+// ToBuilder returns a builder holding a copy of every field of err, including
+// its cause, so that fields can be added to the same layer instead of wrapping
+// it in a new one. Build re-emits it; Error also replaces the message. The stack
+// trace is captured again by the terminal call. A nil err gives an empty builder.
+//
+//	enriched := errors.ToBuilder(err).WithTag("billing").Build()
+func ToBuilder(err *Error) ErrorBuilder {
+	if err == nil {
+		return newBuilder()
+	}
+	return err.clone()
+}
+
+func HTTPStatus(status int) ErrorBuilder {
+	return newBuilder().HTTPStatus(status)
+}
+
 func Reason(reason string) ErrorBuilder {
 	return newBuilder().Reason(reason)
 }
@@ -32,12 +88,20 @@ func WithMetadata(key, value string) ErrorBuilder {
 	return newBuilder().WithMetadata(key, value)
 }
 
+func WithMetadataMap(m map[string]string) ErrorBuilder {
+	return newBuilder().WithMetadataMap(m)
+}
+
+func WithAttribute(key string, value any) ErrorBuilder {
+	return newBuilder().WithAttribute(key, value)
+}
+
 func WithQuotaViolation(subject string, description string) ErrorBuilder {
 	return newBuilder().WithQuotaViolation(subject, description)
 }
 
-func WithPreconditionViolation(subject string, description string) ErrorBuilder {
-	return newBuilder().WithPreconditionViolation(subject, description)
+func WithPreconditionViolation(typ string, subject string, description string) ErrorBuilder {
+	return newBuilder().WithPreconditionViolation(typ, subject, description)
 }
 
 func WithFieldViolation(field string, description string) ErrorBuilder {
@@ -48,6 +112,10 @@ func WithLocalization(localization Localization) ErrorBuilder {
 	return newBuilder().WithLocalization(localization)
 }
 
+func LocalizationKey(key string) ErrorBuilder {
+	return newBuilder().LocalizationKey(key)
+}
+
 func UserID(userID string) ErrorBuilder {
 	return newBuilder().UserID(userID)
 }
@@ -67,3 +135,31 @@ func Span(span string) ErrorBuilder {
 func WithTag(tag string) ErrorBuilder {
 	return newBuilder().WithTag(tag)
 }
+
+func WithTags(tags ...string) ErrorBuilder {
+	return newBuilder().WithTags(tags...)
+}
+
+func Reported() ErrorBuilder {
+	return newBuilder().Reported()
+}
+
+func Source(file string, line int, function string) ErrorBuilder {
+	return newBuilder().Source(file, line, function)
+}
+
+func StackDepth(n int) ErrorBuilder {
+	return newBuilder().StackDepth(n)
+}
+
+func SkipCallers(n int) ErrorBuilder {
+	return newBuilder().SkipCallers(n)
+}
+
+func RetryPolicy(delay time.Duration, maxAttempts int) ErrorBuilder {
+	return newBuilder().RetryPolicy(delay, maxAttempts)
+}
+
+func RetryAfter(d time.Duration) ErrorBuilder {
+	return newBuilder().RetryAfter(d)
+}
@@ -1,11 +1,10 @@
 package errors
 
 import (
-	"errors"
 	"fmt"
+	"sync"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/samber/lo"
 )
 
@@ -13,12 +12,17 @@ type ErrorBuilder Error
 
 func newBuilder() ErrorBuilder {
 	return ErrorBuilder{
-		err:     nil,
-		message: nil,
-
-		reason:   nil,
-		domain:   nil,
-		metadata: nil,
+		err:              nil,
+		message:          nil,
+		messageFromCause: false,
+
+		code:       "",
+		severity:   0,
+		httpStatus: nil,
+		reason:     nil,
+		domain:     nil,
+		metadata:   nil,
+		attributes: nil,
 
 		quotaViolations:        nil,
 		preconditionViolations: nil,
@@ -27,19 +31,89 @@ func newBuilder() ErrorBuilder {
 		userID:   nil,
 		tenantID: nil,
 
-		trace:     nil,
-		span:      nil,
-		requestID: nil,
-		tags:      nil,
-		time:      time.Now(),
+		trace:        nil,
+		span:         nil,
+		spanKind:     SpanKindUnspecified,
+		spanOnDemand: false,
+		requestID:    nil,
+		tags:         nil,
+		time:         now(),
+
+		help:            Help{},
+		resource:        Resource{},
+		localizations:   nil,
+		localizationKey: nil,
+		retry:           Retry{},
+
+		reported: false,
+
+		stackTrace:         nil,
+		importedStackTrace: nil,
+		source:             nil,
+		stackDepth:         nil,
+		skipCallers:        0,
+		goroutineID:        0,
+
+		lazy: nil,
+	}
+}
+
+// builderPool recycles the builders of AcquireBuilder. Their maps and slices
+// are allocated up front, so that the builders derived from them by value write
+// into the same storage, which reset then keeps for the next use.
+var builderPool = sync.Pool{
+	New: func() any {
+		return &ErrorBuilder{
+			metadata:        make(map[string]string, 8),
+			attributes:      make(map[string]any, 8),
+			fieldViolations: make([]FieldViolation, 0, 4),
+			tags:            make([]string, 0, 4),
+		}
+	},
+}
 
-		help:          Help{},
-		resource:      Resource{},
-		localizations: nil,
-		retry:         Retry{},
+// AcquireBuilder returns an empty builder from a pool, for hot paths that build
+// many errors with metadata, violations or tags: the maps and slices of a
+// released builder are reused instead of being allocated again. The errors built
+// from it copy them, so they stay valid after ReleaseBuilder, but the builder
+// itself and the builders derived from it must not be used afterwards:
+//
+//	b := errors.AcquireBuilder()
+//	defer errors.ReleaseBuilder(b)
+//	return b.Reason("ERROR_REASON_QUOTA").WithMetadata("user", userID).Wrap(err)
+func AcquireBuilder() *ErrorBuilder {
+	b := builderPool.Get().(*ErrorBuilder)
+	b.time = now()
+	return b
+}
 
-		stackTrace: nil,
+// ReleaseBuilder resets b and returns it to the pool of AcquireBuilder.
+func ReleaseBuilder(b *ErrorBuilder) {
+	if b == nil {
+		return
 	}
+	b.reset()
+	builderPool.Put(b)
+}
+
+// reset empties the builder, keeping the storage of its maps and slices.
+func (e *ErrorBuilder) reset() {
+	metadata, attributes := e.metadata, e.attributes
+	clear(metadata)
+	clear(attributes)
+	quotaViolations := e.quotaViolations[:0]
+	preconditionViolations := e.preconditionViolations[:0]
+	fieldViolations := e.fieldViolations[:0]
+	tags := e.tags[:0]
+	localizations := e.localizations[:0]
+
+	*e = newBuilder()
+	e.metadata, e.attributes = metadata, attributes
+	e.quotaViolations = quotaViolations
+	e.preconditionViolations = preconditionViolations
+	e.fieldViolations = fieldViolations
+	e.tags = tags
+	e.localizations = localizations
 }
 
 func (e ErrorBuilder) Wrap(err error) error {
@@ -62,19 +136,50 @@ func (e ErrorBuilder) Wrapf(err error, format string, args ...any) error {
 func (e ErrorBuilder) Error(message string) error {
 	e2 := e.deepCopy()
 	e2.message = &message
-	e2.stackTrace = newStacktrace()
+	e2.messageFromCause = false
+	e2.captureStackTrace()
 	return (*Error)(&e2)
 }
 
+// Errorf keeps the formatted error as cause, so that errors wrapped with %w
+// can be matched with Is, and uses its text as message.
 func (e ErrorBuilder) Errorf(format string, args ...any) error {
 	e2 := e.deepCopy()
 	e2.err = fmt.Errorf(format, args...)
-	e2.stackTrace = newStacktrace()
+	e2.message = lo.ToPtr(e2.err.Error())
+	e2.messageFromCause = true
+	e2.captureStackTrace()
+	return (*Error)(&e2)
+}
+
+// Build returns the error as a concrete *Error, for callers who need its
+// accessors right away. The error is valid even without a message or a cause.
+func (e ErrorBuilder) Build() *Error {
+	e2 := e.deepCopy()
+	e2.captureStackTrace()
 	return (*Error)(&e2)
 }
 
+// Join returns an error holding every non-nil error of errs as a branch, or nil
+// if there is none. Is and As match any branch, and the accessors that walk the
+// chain, such as Tags, visit every branch in order.
 func (e ErrorBuilder) Join(errs ...error) error {
-	return e.Wrap(errors.Join(errs...))
+	joined := newJoinError(errs)
+	if joined == nil {
+		return nil
+	}
+	return e.Wrap(joined)
+}
+
+// WithCauseChain attaches a pre-built chain, typically from another error
+// library, as the cause in one call. Accessors, Is and As follow the chain
+// through Unwrap as well as Cause() methods, so the first *Error found in it is
+// the one accessors resolve into.
+func (e ErrorBuilder) WithCauseChain(root error) error {
+	if root == nil {
+		return nil
+	}
+	return e.Wrap(&causeChain{err: root})
 }
 
 func (e ErrorBuilder) wrap(err error) *ErrorBuilder {
@@ -83,14 +188,45 @@ func (e ErrorBuilder) wrap(err error) *ErrorBuilder {
 	}
 	e2 := e.deepCopy()
 	e2.err = err
-	if e2.span == nil {
-		e2.span = lo.ToPtr(uuid.NewString()) // TODO: use a unique identifier
-	}
-	e2.stackTrace = newStacktrace()
+	e2.messageFromCause = false
+	// The span is generated by Span on first use, on the layer it is read from,
+	// and only if this layer turns out to be the innermost *Error.
+	e2.spanOnDemand = e2.span == nil
+	e2.captureStackTrace()
 
 	return &e2
 }
 
+// If applies fn to the builder when cond is true, and returns the builder
+// unchanged otherwise, to add optional fields without breaking the chain:
+//
+//	errors.Reason("ERROR_REASON_INVALID_TOKEN").
+//		If(userID != "", func(b errors.ErrorBuilder) errors.ErrorBuilder {
+//			return b.UserID(userID)
+//		}).
+//		Error("invalid token")
+func (e ErrorBuilder) If(cond bool, fn func(ErrorBuilder) ErrorBuilder) ErrorBuilder {
+	if !cond {
+		return e
+	}
+	return fn(e)
+}
+
+func (e ErrorBuilder) Code(code Code) ErrorBuilder {
+	e.code = code
+	return e
+}
+
+func (e ErrorBuilder) Severity(severity Severity) ErrorBuilder {
+	e.severity = severity
+	return e
+}
+
+func (e ErrorBuilder) HTTPStatus(status int) ErrorBuilder {
+	e.httpStatus = &status
+	return e
+}
+
 func (e ErrorBuilder) Reason(reason string) ErrorBuilder {
 	e.reason = &reason
 	return e
@@ -109,6 +245,32 @@ func (e ErrorBuilder) WithMetadata(key, value string) ErrorBuilder {
 	return e
 }
 
+// WithMetadataMap adds every entry of m to the metadata, overwriting the keys
+// already set.
+func (e ErrorBuilder) WithMetadataMap(m map[string]string) ErrorBuilder {
+	if len(m) == 0 {
+		return e
+	}
+	if e.metadata == nil {
+		e.metadata = make(map[string]string, len(m))
+	}
+	for key, value := range m {
+		e.metadata[key] = value
+	}
+	return e
+}
+
+// WithAttribute sets a metadata value that keeps its type, such as a number, a
+// bool or a struct, in MarshalJSON and LogValue, unlike WithMetadata which
+// takes strings only.
+func (e ErrorBuilder) WithAttribute(key string, value any) ErrorBuilder {
+	if e.attributes == nil {
+		e.attributes = map[string]any{}
+	}
+	e.attributes[key] = value
+	return e
+}
+
 func (e ErrorBuilder) WithQuotaViolation(subject string, description string) ErrorBuilder {
 	e.quotaViolations = append(e.quotaViolations, QuotaViolation{
 		Subject:     subject,
@@ -117,8 +279,9 @@ func (e ErrorBuilder) WithQuotaViolation(subject string, description string) Err
 	return e
 }
 
-func (e ErrorBuilder) WithPreconditionViolation(subject string, description string) ErrorBuilder {
+func (e ErrorBuilder) WithPreconditionViolation(typ string, subject string, description string) ErrorBuilder {
 	e.preconditionViolations = append(e.preconditionViolations, PreconditionViolation{
+		Type:        typ,
 		Subject:     subject,
 		Description: description,
 	})
@@ -153,6 +316,11 @@ func (e ErrorBuilder) Span(span string) ErrorBuilder {
 	return e
 }
 
+func (e ErrorBuilder) SpanKind(kind SpanKind) ErrorBuilder {
+	e.spanKind = kind
+	return e
+}
+
 func (e ErrorBuilder) RequestID(requestID string) ErrorBuilder {
 	e.requestID = &requestID
 	return e
@@ -163,6 +331,11 @@ func (e ErrorBuilder) WithTag(tag string) ErrorBuilder {
 	return e
 }
 
+func (e ErrorBuilder) WithTags(tags ...string) ErrorBuilder {
+	e.tags = append(e.tags, tags...)
+	return e
+}
+
 func (e ErrorBuilder) Help(help Help) ErrorBuilder {
 	e.help = help
 	return e
@@ -173,40 +346,156 @@ func (e ErrorBuilder) Resource(resource Resource) ErrorBuilder {
 	return e
 }
 
+// WithLocalization adds a localized message. A well-formed locale is
+// canonicalized as a BCP 47 tag; a malformed one is kept as is. Use
+// NewLocalization to reject malformed locales.
 func (e ErrorBuilder) WithLocalization(localization Localization) ErrorBuilder {
+	if tag, err := localization.Tag(); err == nil {
+		localization.Locale = tag.String()
+	}
 	e.localizations = append(e.localizations, localization)
 	return e
 }
 
+// LocalizationKey references a catalog entry instead of inlining localized
+// messages. The key is resolved by LocalizedMessage at render time.
+func (e ErrorBuilder) LocalizationKey(key string) ErrorBuilder {
+	e.localizationKey = &key
+	return e
+}
+
 func (e ErrorBuilder) Retry(retry Retry) ErrorBuilder {
 	e.retry = retry
 	return e
 }
 
+// RetryPolicy sets a retry policy of at most maxAttempts attempts, delay apart.
+func (e ErrorBuilder) RetryPolicy(delay time.Duration, maxAttempts int) ErrorBuilder {
+	e.retry = Retry{Delay: delay, MaxAttempts: maxAttempts}
+	return e
+}
+
+// RetryAfter sets the delay of the retry policy, keeping its other fields.
+func (e ErrorBuilder) RetryAfter(d time.Duration) ErrorBuilder {
+	e.retry.Delay = d
+	return e
+}
+
+// Backoff sets how the delay of the retry policy grows with the attempts. The
+// delay of the policy is the base of the backoff.
+func (e ErrorBuilder) Backoff(strategy BackoffStrategy, multiplier float64, maxDelay time.Duration) ErrorBuilder {
+	e.retry.Strategy = strategy
+	e.retry.Multiplier = multiplier
+	e.retry.MaxDelay = maxDelay
+	return e
+}
+
+// Reported marks the error as already reported to an incident system, so that
+// alerting middleware can skip it. The mark survives wrapping.
+func (e ErrorBuilder) Reported() ErrorBuilder {
+	e.reported = true
+	return e
+}
+
+// WithStackTraceString attaches a stack trace that was rendered elsewhere, such
+// as in another process, so that StackTrace and %+v include it. It is kept as
+// text: no frames can be recovered from it.
+func (e ErrorBuilder) WithStackTraceString(s string) ErrorBuilder {
+	e.importedStackTrace = &s
+	return e
+}
+
+// Source sets the top frame of the stack trace captured when the error is
+// built, so that factory functions can report the location they were called
+// for instead of their own.
+func (e ErrorBuilder) Source(file string, line int, function string) ErrorBuilder {
+	e.source = &stackTraceFrame{
+		file:     file,
+		function: function,
+		line:     line,
+	}
+	return e
+}
+
+// StackDepth sets the maximum number of frames captured when the error is
+// built, instead of StackTraceMaxDepth. A depth of 0 captures no stack trace.
+func (e ErrorBuilder) StackDepth(n int) ErrorBuilder {
+	e.stackDepth = &n
+	return e
+}
+
+// SkipCallers drops the n innermost frames of the stack trace captured when the
+// error is built, after the frames of this package, so that helpers wrapping
+// this package don't appear as the location the error was thrown from.
+func (e ErrorBuilder) SkipCallers(n int) ErrorBuilder {
+	e.skipCallers = n
+	return e
+}
+
+func (e *ErrorBuilder) captureStackTrace() {
+	e.stackTrace = newStacktrace(lo.FromPtrOr(e.stackDepth, StackTraceMaxDepth), e.skipCallers)
+	if e.source != nil {
+		if len(e.stackTrace) == 0 {
+			e.stackTrace = stackTrace{*e.source}
+		} else {
+			e.stackTrace[0] = *e.source
+		}
+	}
+	e.goroutineID = currentGoroutineID()
+}
+
+// clone returns a builder holding a deep copy of every field of the error,
+// including its stack trace.
+func (e *Error) clone() ErrorBuilder {
+	e2 := ErrorBuilder(*e).deepCopy()
+	e2.stackTrace = e.stackTrace
+	e2.goroutineID = e.goroutineID
+	return e2
+}
+
 func (e ErrorBuilder) deepCopy() ErrorBuilder {
 	return ErrorBuilder{
-		err:      e.err,
-		message:  deepCopyPtr(e.message),
-		reason:   deepCopyPtr(e.reason),
-		domain:   deepCopyPtr(e.domain),
-		metadata: lo.Assign(map[string]string{}, e.metadata),
-
-		quotaViolations:        lo.Slice(e.quotaViolations, 0, len(e.quotaViolations)),
-		preconditionViolations: lo.Slice(e.preconditionViolations, 0, len(e.preconditionViolations)),
-		fieldViolations:        lo.Slice(e.fieldViolations, 0, len(e.fieldViolations)),
+		err:              e.err,
+		message:          deepCopyPtr(e.message),
+		messageFromCause: e.messageFromCause,
+		code:             e.code,
+		severity:         e.severity,
+		httpStatus:       deepCopyPtr(e.httpStatus),
+		reason:           deepCopyPtr(e.reason),
+		domain:           deepCopyPtr(e.domain),
+		metadata:         cloneMap(e.metadata),
+		attributes:       cloneMap(e.attributes),
+
+		quotaViolations:        cloneSlice(e.quotaViolations),
+		preconditionViolations: cloneSlice(e.preconditionViolations),
+		fieldViolations:        cloneSlice(e.fieldViolations),
 
 		userID:   deepCopyPtr(e.userID),
 		tenantID: deepCopyPtr(e.tenantID),
 
-		trace: deepCopyPtr(e.trace),
-		span:  deepCopyPtr(e.span),
-		tags:  lo.Slice(e.tags, 0, len(e.tags)),
-
-		help:          e.help,
-		resource:      e.resource,
-		localizations: lo.Slice(e.localizations, 0, len(e.localizations)),
-		retry:         e.retry,
-
-		stackTrace: nil,
+		trace:        deepCopyPtr(e.trace),
+		span:         deepCopyPtr(e.span),
+		spanOnDemand: e.spanOnDemand,
+		spanKind:     e.spanKind,
+		requestID:    deepCopyPtr(e.requestID),
+		tags:         cloneSlice(e.tags),
+		time:         e.time,
+
+		help:            e.help,
+		resource:        e.resource,
+		localizations:   cloneSlice(e.localizations),
+		localizationKey: deepCopyPtr(e.localizationKey),
+		retry:           e.retry,
+
+		reported: e.reported,
+
+		stackTrace:         nil,
+		importedStackTrace: deepCopyPtr(e.importedStackTrace),
+		source:             deepCopyPtr(e.source),
+		stackDepth:         deepCopyPtr(e.stackDepth),
+		skipCallers:        e.skipCallers,
+		goroutineID:        0,
+
+		lazy: &lazyState{},
 	}
 }
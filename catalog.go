@@ -0,0 +1,39 @@
+package errors
+
+import (
+	"sync/atomic"
+)
+
+// Catalog maps localization keys to their messages by locale.
+//
+//	errors.Catalog{
+//		"INVALID_REFRESH_TOKEN": {
+//			"en": "The refresh token is invalid.",
+//			"ko": "refresh token이 유효하지 않습니다.",
+//		},
+//	}
+type Catalog map[string]map[string]string
+
+var catalog atomic.Pointer[Catalog]
+
+// SetCatalog replaces the catalog used to resolve localization keys.
+func SetCatalog(c Catalog) {
+	catalog.Store(&c)
+}
+
+func getCatalog() Catalog {
+	c := catalog.Load()
+	if c == nil {
+		return nil
+	}
+	return *c
+}
+
+func (c Catalog) lookup(key string, locale string) (string, bool) {
+	messages, ok := c[key]
+	if !ok {
+		return "", false
+	}
+	message, ok := messages[locale]
+	return message, ok
+}
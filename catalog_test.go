@@ -0,0 +1,174 @@
+package errors_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/notjustmoney/errors"
+)
+
+func TestLocalizedMessage(t *testing.T) {
+	is := assert.New(t)
+
+	errors.SetCatalog(errors.Catalog{
+		"INVALID_REFRESH_TOKEN": {
+			"ko": "refresh token이 유효하지 않습니다.",
+		},
+	})
+	defer errors.SetCatalog(nil)
+
+	var err *errors.Error
+	is.ErrorAs(
+		errors.Wrap(errors.
+			Reason("ERROR_REASON_INVALID_REFRESH_TOKEN").
+			LocalizationKey("INVALID_REFRESH_TOKEN").
+			WithLocalization(errors.Localization{Locale: "en", Message: "Invalid refresh token"}).
+			Errorf("invalid refresh token")),
+		&err,
+	)
+
+	is.Equal("refresh token이 유효하지 않습니다.", err.LocalizedMessage("ko"))
+	is.Equal("Invalid refresh token", err.LocalizedMessage("en"))
+	is.Equal("ERROR_REASON_INVALID_REFRESH_TOKEN", err.LocalizedMessage("ja"))
+}
+
+func TestLocalize(t *testing.T) {
+	is := assert.New(t)
+
+	errors.SetCatalog(errors.Catalog{
+		"INVALID_REFRESH_TOKEN": {
+			"ko": "refresh token이 유효하지 않습니다.",
+		},
+	})
+	defer errors.SetCatalog(nil)
+
+	var err *errors.Error
+	is.ErrorAs(
+		errors.
+			LocalizationKey("INVALID_REFRESH_TOKEN").
+			WithLocalization(errors.Localization{Locale: "en", Message: "Invalid refresh token"}).
+			WithLocalization(errors.Localization{Locale: "zh-Hant", Message: "無效的刷新令牌"}).
+			Errorf("invalid refresh token"),
+		&err,
+	)
+
+	message, ok := err.Localize("ko-KR")
+	is.True(ok)
+	is.Equal("refresh token이 유효하지 않습니다.", message)
+
+	message, ok = err.Localize("zh_Hant_TW")
+	is.True(ok)
+	is.Equal("無效的刷新令牌", message)
+
+	message, ok = err.Localize("ja-JP", "en")
+	is.True(ok)
+	is.Equal("Invalid refresh token", message)
+
+	message, ok = err.Localize("ja-JP")
+	is.False(ok)
+	is.Empty(message)
+}
+
+func TestLocalizationTag(t *testing.T) {
+	is := assert.New(t)
+
+	for locale, want := range map[string]string{
+		"en":      "en",
+		"en-us":   "en-US",
+		"zh-hant": "zh-Hant",
+	} {
+		l, err := errors.NewLocalization(locale, "message")
+		is.NoError(err)
+		is.Equal(want, l.Locale)
+
+		tag, err := l.Tag()
+		is.NoError(err)
+		is.Equal(want, tag.String())
+	}
+
+	_, err := errors.NewLocalization("garbage", "message")
+	is.Error(err)
+	_, err = errors.Localization{Locale: "garbage"}.Tag()
+	is.Error(err)
+
+	var e *errors.Error
+	is.ErrorAs(
+		errors.
+			WithLocalization(errors.Localization{Locale: "en-us", Message: "normalized"}).
+			WithLocalization(errors.Localization{Locale: "garbage", Message: "kept"}).
+			Errorf("invalid refresh token"),
+		&e,
+	)
+	is.Equal([]errors.Localization{
+		{Locale: "en-US", Message: "normalized"},
+		{Locale: "garbage", Message: "kept"},
+	}, e.Localizations())
+}
+
+func TestDefaultLocale(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(
+		errors.
+			Reason("ERROR_REASON_INVALID_REFRESH_TOKEN").
+			WithLocalization(errors.Localization{Locale: "en", Message: "Invalid refresh token"}).
+			Errorf("invalid refresh token"),
+		&err,
+	)
+
+	_, ok := err.Localize("ko-KR", "ja")
+	is.False(ok)
+
+	errors.SetDefaultLocale("en")
+	defer errors.SetDefaultLocale("")
+
+	message, ok := err.Localize("ko-KR", "ja")
+	is.True(ok)
+	is.Equal("Invalid refresh token", message)
+	is.Equal("Invalid refresh token", err.LocalizedMessage("ko"))
+}
+
+func TestLocalizef(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(
+		errors.
+			WithLocalization(errors.Localization{
+				Locale:  "en",
+				Message: "{count} of {limit} refreshes remaining for {user}",
+				Args:    map[string]string{"limit": "10"},
+			}).
+			WithLocalization(errors.Localization{Locale: "ko", Message: "토큰이 만료되었습니다."}).
+			Errorf("too many refreshes"),
+		&err,
+	)
+
+	message, ok := err.Localize("en")
+	is.True(ok)
+	is.Equal("{count} of 10 refreshes remaining for {user}", message)
+
+	message, ok = err.Localizef("en", map[string]any{"count": 3, "limit": 5})
+	is.True(ok)
+	is.Equal("3 of 5 refreshes remaining for {user}", message)
+
+	message, ok = err.Localizef("en-GB", struct {
+		Count int
+		user  string
+	}{Count: 3, user: "unexported"})
+	is.True(ok)
+	is.Equal("3 of 10 refreshes remaining for {user}", message)
+
+	message, ok = err.Localizef("en", map[string]string{"count": "1", "user": "user/42"})
+	is.True(ok)
+	is.Equal("1 of 10 refreshes remaining for user/42", message)
+
+	message, ok = err.Localizef("ko", map[string]any{"count": 3})
+	is.True(ok)
+	is.Equal("토큰이 만료되었습니다.", message)
+
+	_, ok = err.Localizef("ja", nil)
+	is.False(ok)
+}
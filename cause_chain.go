@@ -0,0 +1,51 @@
+package errors
+
+import (
+	"errors"
+)
+
+// causeChain adapts a foreign error chain so that errors.Is and errors.As also
+// follow the Cause() method used by other error libraries, in addition to
+// Unwrap.
+type causeChain struct {
+	err error
+}
+
+func (c *causeChain) Error() string {
+	return c.err.Error()
+}
+
+func (c *causeChain) Unwrap() error {
+	return c.err
+}
+
+func (c *causeChain) Is(target error) bool {
+	for err := causeOf(c.err); err != nil; err = causeOf(err) {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *causeChain) As(target any) bool {
+	for err := causeOf(c.err); err != nil; err = causeOf(err) {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// causeOf returns the error behind the first Cause() method found while
+// unwrapping err, or nil if there is none.
+func causeOf(err error) error {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if c, ok := err.(interface{ Cause() error }); ok {
+			if cause := c.Cause(); cause != err {
+				return cause
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package errors
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+var clock atomic.Pointer[func() time.Time]
+
+// SetClock sets the function that returns the time errors are created at, e.g.
+// to freeze time in tests. A nil clock restores the default, time.Now.
+func SetClock(now func() time.Time) {
+	if now == nil {
+		clock.Store(nil)
+		return
+	}
+	clock.Store(&now)
+}
+
+func now() time.Time {
+	c := clock.Load()
+	if c == nil {
+		return time.Now()
+	}
+	return (*c)()
+}
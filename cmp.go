@@ -0,0 +1,74 @@
+package errors
+
+import (
+	"github.com/google/go-cmp/cmp"
+)
+
+// CmpOptions returns the go-cmp options needed to compare errors of this package
+// in tests:
+//
+//	if diff := cmp.Diff(want, got, errors.CmpOptions()...); diff != "" {
+//		t.Errorf("mismatch (-want +got):\n%s", diff)
+//	}
+//
+// Errors are compared through their accessors. The volatile fields (trace,
+// span, time and stack trace) are ignored.
+func CmpOptions() []cmp.Option {
+	return []cmp.Option{
+		cmp.Transformer("errors.Error", func(e *Error) *cmpError {
+			if e == nil {
+				return nil
+			}
+			return &cmpError{
+				Message:                e.Error(),
+				Code:                   e.Code(),
+				Severity:               e.Severity(),
+				HTTPStatus:             e.HTTPStatus(),
+				Reason:                 e.Reason(),
+				Domain:                 e.Domain(),
+				Metadata:               e.Metadata(),
+				Attributes:             e.Attributes(),
+				QuotaViolations:        e.QuotaViolations(),
+				PreconditionViolations: e.PreconditionViolations(),
+				FieldViolations:        e.FieldViolations(),
+				UserID:                 e.UserID(),
+				TenantID:               e.TenantID(),
+				RequestID:              e.RequestID(),
+				SpanKind:               e.SpanKind(),
+				Tags:                   e.Tags(),
+				Help:                   e.Help(),
+				Resource:               e.Resource(),
+				Localizations:          e.Localizations(),
+				LocalizationKey:        e.LocalizationKey(),
+				Retry:                  e.Retry(),
+				Reported:               e.IsReported(),
+			}
+		}),
+	}
+}
+
+// cmpError is the comparable view of an *Error used by CmpOptions.
+type cmpError struct {
+	Message                string
+	Code                   Code
+	Severity               Severity
+	HTTPStatus             int
+	Reason                 *string
+	Domain                 *string
+	Metadata               map[string]string
+	Attributes             map[string]any
+	QuotaViolations        []QuotaViolation
+	PreconditionViolations []PreconditionViolation
+	FieldViolations        []FieldViolation
+	UserID                 *string
+	TenantID               *string
+	RequestID              *string
+	SpanKind               SpanKind
+	Tags                   []string
+	Help                   Help
+	Resource               Resource
+	Localizations          []Localization
+	LocalizationKey        *string
+	Retry                  Retry
+	Reported               bool
+}
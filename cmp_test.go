@@ -0,0 +1,26 @@
+package errors_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/notjustmoney/errors"
+)
+
+func TestCmpOptions(t *testing.T) {
+	is := assert.New(t)
+
+	builder := errors.
+		Reason("ERROR_REASON_INVALID_REFRESH_TOKEN").
+		Domain("identity").
+		WithFieldViolation("refreshToken", "refresh-token-string")
+
+	want := builder.Error("invalid refresh token")
+	got := errors.Trace("trace").Wrap(builder.Error("invalid refresh token"))
+	is.Empty(cmp.Diff(want, got, errors.CmpOptions()...))
+
+	other := builder.Reason("ERROR_REASON_EXPIRED_REFRESH_TOKEN").Error("invalid refresh token")
+	is.NotEmpty(cmp.Diff(want, other, errors.CmpOptions()...))
+}
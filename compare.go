@@ -2,8 +2,64 @@ package errors
 
 import (
 	"errors"
+
+	"github.com/samber/lo"
 )
 
+// Is reports whether any error in err's tree matches target, with the same
+// result as errors.Is. When target is an *Error with a reason and no cause, such
+// as a sentinel declared with Reason(...).Build(), which (*Error).Is matches by
+// reason, the reasons are compared before delegating to errors.Is, which avoids
+// walking deep chains of *Error through reflection.
 func Is(err, target error) bool {
+	if t, ok := target.(*Error); ok && isReasonSentinel(t) {
+		if hasReason(err, *t.reason) {
+			return true
+		}
+	}
 	return errors.Is(err, target)
 }
+
+// isReasonSentinel reports whether e is matched by reason rather than by
+// identity: it has a reason and no cause.
+func isReasonSentinel(e *Error) bool {
+	return e != nil && e.reason != nil && e.err == nil
+}
+
+// hasReason walks the tree of err without reflection and reports whether an
+// *Error of it has the given reason.
+func hasReason(err error, reason string) bool {
+	for err != nil {
+		switch x := err.(type) {
+		case *Error:
+			if x == nil {
+				return false
+			}
+			if x.reason != nil && *x.reason == reason {
+				return true
+			}
+			err = x.err
+		case interface{ Unwrap() error }:
+			err = x.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, branch := range x.Unwrap() {
+				if hasReason(branch, reason) {
+					return true
+				}
+			}
+			return false
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// IsRetryable reports whether err is, or wraps, an *Error with a retry policy.
+func IsRetryable(err error) bool {
+	var e *Error
+	if !errors.As(err, &e) {
+		return false
+	}
+	return !lo.IsEmpty(e.Retry())
+}
@@ -0,0 +1,108 @@
+package errors_test
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io/fs"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/notjustmoney/errors"
+)
+
+var errUserNotFound = errors.Reason("ERROR_REASON_USER_NOT_FOUND").Build()
+
+func TestIsReason(t *testing.T) {
+	is := assert.New(t)
+
+	is.True(errors.Is(errors.Wrap(errUserNotFound), errUserNotFound))
+	is.True(errors.Is(errors.Reason("ERROR_REASON_USER_NOT_FOUND").Error("user not found"), errUserNotFound))
+	is.False(errors.Is(errors.Reason("ERROR_REASON_ORDER_NOT_FOUND").Error("order not found"), errUserNotFound))
+
+	is.True(errors.Is(errors.Reason("ERROR_REASON_USER_NOT_FOUND").Wrap(fs.ErrNotExist), fs.ErrNotExist))
+	is.False(errors.Is(errors.Wrap(fs.ErrNotExist), errUserNotFound))
+	is.False(errors.Is((*errors.Error)(nil), errUserNotFound))
+
+	for _, err := range []error{
+		errors.Wrap(errUserNotFound),
+		errors.Reason("ERROR_REASON_USER_NOT_FOUND").Error("user not found"),
+		errors.Wrap(fmt.Errorf("lookup: %w", errors.Reason("ERROR_REASON_USER_NOT_FOUND").Error("user not found"))),
+		errors.Reason("ERROR_REASON_ORDER_NOT_FOUND").Error("order not found"),
+		errors.Wrap(fs.ErrNotExist),
+	} {
+		is.Equal(stderrors.Is(err, errUserNotFound), errors.Is(err, errUserNotFound), err.Error())
+	}
+	is.True(stderrors.Is(errors.Reason("ERROR_REASON_USER_NOT_FOUND").Error("user not found"), errUserNotFound))
+}
+
+func TestIsRetryable(t *testing.T) {
+	is := assert.New(t)
+
+	is.True(errors.IsRetryable(errors.Wrap(errors.RetryPolicy(time.Second, 3).Error("unavailable"))))
+	is.True(errors.IsRetryable(fmt.Errorf("call: %w", errors.ResourceExhausted("user/42", "rate limit", time.Second))))
+	is.False(errors.IsRetryable(errors.New("invalid argument")))
+	is.False(errors.IsRetryable(fs.ErrNotExist))
+	is.False(errors.IsRetryable(nil))
+}
+
+type queryError struct {
+	query string
+}
+
+func (e *queryError) Error() string {
+	return "query failed: " + e.query
+}
+
+func TestAs(t *testing.T) {
+	is := assert.New(t)
+
+	inner := errors.Reason("ERROR_REASON_QUERY").Wrap(&queryError{query: "SELECT 1"})
+	outer := errors.Wrap(inner)
+
+	var e *errors.Error
+	is.True(stderrors.As(outer, &e))
+	is.Same(outer, e)
+
+	var qe *queryError
+	is.True(stderrors.As(fmt.Errorf("handler: %w", outer), &qe))
+	is.Equal("SELECT 1", qe.query)
+
+	var pe *fs.PathError
+	is.False(stderrors.As(outer, &pe))
+}
+
+func deepChain(depth int) error {
+	err := errors.Wrap(errUserNotFound)
+	for i := 0; i < depth; i++ {
+		err = errors.Wrap(err)
+	}
+	return err
+}
+
+func BenchmarkIs(b *testing.B) {
+	err := deepChain(50)
+	other := errors.Reason("ERROR_REASON_ORDER_NOT_FOUND").Build()
+
+	b.Run("stdlib/match", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			stderrors.Is(err, errUserNotFound)
+		}
+	})
+	b.Run("reason/match", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			errors.Is(err, errUserNotFound)
+		}
+	})
+	b.Run("stdlib/miss", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			stderrors.Is(err, other)
+		}
+	})
+	b.Run("reason/miss", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			errors.Is(err, other)
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package errors
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// CorrelationExtractor extracts the correlation identifiers carried by a
+// context. Empty values are ignored.
+type CorrelationExtractor func(ctx context.Context) (trace, span, requestID string)
+
+var correlationExtractor atomic.Pointer[CorrelationExtractor]
+
+// SetCorrelationExtractor sets the function WithContext uses to read the trace,
+// span and request ID from a context. This decouples correlation from any
+// tracing library. Without an extractor, WithContext does nothing.
+func SetCorrelationExtractor(extractor CorrelationExtractor) {
+	if extractor == nil {
+		correlationExtractor.Store(nil)
+		return
+	}
+	correlationExtractor.Store(&extractor)
+}
+
+// WithContext sets the trace, span and request ID from the correlation
+// identifiers of ctx.
+func (e ErrorBuilder) WithContext(ctx context.Context) ErrorBuilder {
+	extractor := correlationExtractor.Load()
+	if extractor == nil || ctx == nil {
+		return e
+	}
+
+	trace, span, requestID := (*extractor)(ctx)
+	if trace != "" {
+		e = e.Trace(trace)
+	}
+	if span != "" {
+		e = e.Span(span)
+	}
+	if requestID != "" {
+		e = e.RequestID(requestID)
+	}
+	return e
+}
+
+// FromContext starts a builder with the correlation identifiers of ctx.
+func FromContext(ctx context.Context) ErrorBuilder {
+	return newBuilder().WithContext(ctx)
+}
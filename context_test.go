@@ -0,0 +1,32 @@
+package errors_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/notjustmoney/errors"
+)
+
+type correlationKey struct{}
+
+func TestWithContext(t *testing.T) {
+	is := assert.New(t)
+
+	ctx := context.WithValue(context.Background(), correlationKey{}, "trace-1")
+
+	err := errors.FromContext(ctx).Build()
+	is.Nil(err.Span())
+
+	errors.SetCorrelationExtractor(func(ctx context.Context) (string, string, string) {
+		trace, _ := ctx.Value(correlationKey{}).(string)
+		return trace, "span-1", ""
+	})
+	defer errors.SetCorrelationExtractor(nil)
+
+	err = errors.FromContext(ctx).Build()
+	is.Equal("trace-1", *err.Trace())
+	is.Equal("span-1", *err.Span())
+	is.Nil(err.RequestID())
+}
@@ -0,0 +1,15 @@
+package errors
+
+import (
+	"sync/atomic"
+)
+
+var inferDomainFromReason atomic.Bool
+
+// SetInferDomainFromReason enables inferring the domain from the reason when
+// reasons are namespaced, like "identity.INVALID_TOKEN". The domain is then the
+// part of the reason before the first dot. An explicit domain always wins, and
+// reasons without a dot have no inferred domain. Disabled by default.
+func SetInferDomainFromReason(enabled bool) {
+	inferDomainFromReason.Store(enabled)
+}
@@ -0,0 +1,45 @@
+// Package errgin renders errors returned by gin handlers. It is kept apart from
+// the errors package so that users who don't need gin don't pull the
+// dependency.
+package errgin
+
+import (
+	stderrors "errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/notjustmoney/errors"
+	"github.com/notjustmoney/errors/errhttp"
+)
+
+// Middleware renders the first *errors.Error attached to the context with
+// c.Error once the handlers have run, unless a response was already written.
+// It is written with errhttp.WriteHTTP, or as RFC 7807 problem details if the
+// request accepts the ProblemContentType media type.
+func Middleware(opts ...errhttp.Option) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if c.Writer.Written() {
+			return
+		}
+
+		for _, ginErr := range c.Errors {
+			var e *errors.Error
+			if !stderrors.As(ginErr.Err, &e) {
+				continue
+			}
+
+			if strings.Contains(c.GetHeader("Accept"), errors.ProblemContentType) {
+				body, err := e.MarshalProblemJSON()
+				if err == nil {
+					c.Data(e.HTTPStatus(), errors.ProblemContentType, body)
+					return
+				}
+			}
+			errhttp.WriteHTTP(c.Writer, e, opts...)
+			return
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package errgin_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/notjustmoney/errors"
+	"github.com/notjustmoney/errors/errgin"
+)
+
+func newRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(errgin.Middleware())
+	r.GET("/users/:id", func(c *gin.Context) {
+		_ = c.Error(errors.NotFound("user", c.Param("id")))
+	})
+	r.GET("/ok", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	return r
+}
+
+func TestMiddleware(t *testing.T) {
+	is := assert.New(t)
+	r := newRouter()
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+	is.Equal("application/json", rec.Header().Get("Content-Type"))
+
+	var body map[string]any
+	is.NoError(json.Unmarshal(rec.Body.Bytes(), &body))
+	is.Equal(string(errors.CodeNotFound), body["code"])
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ok", nil))
+	is.Equal(http.StatusOK, rec.Code)
+	is.Equal("ok", rec.Body.String())
+}
+
+func TestMiddlewareProblem(t *testing.T) {
+	is := assert.New(t)
+	r := newRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	req.Header.Set("Accept", errors.ProblemContentType)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	is.Equal(errors.ProblemContentType, rec.Header().Get("Content-Type"))
+
+	var problem errors.ProblemDetail
+	is.NoError(json.Unmarshal(rec.Body.Bytes(), &problem))
+	is.Equal(rec.Code, problem.Status)
+	is.Equal("NOT_FOUND", problem.Title)
+}
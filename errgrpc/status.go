@@ -0,0 +1,202 @@
+// Package errgrpc converts errors to the google.rpc error model used by gRPC. It
+// is kept apart from the errors package so that users who don't need gRPC don't
+// pull the dependency.
+package errgrpc
+
+import (
+	stderrors "errors"
+
+	"github.com/samber/lo"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/notjustmoney/errors"
+)
+
+var grpcCodes = map[errors.Code]codes.Code{
+	errors.CodeOK:                 codes.OK,
+	errors.CodeCancelled:          codes.Canceled,
+	errors.CodeUnknown:            codes.Unknown,
+	errors.CodeInvalidArgument:    codes.InvalidArgument,
+	errors.CodeDeadlineExceeded:   codes.DeadlineExceeded,
+	errors.CodeNotFound:           codes.NotFound,
+	errors.CodeAlreadyExists:      codes.AlreadyExists,
+	errors.CodePermissionDenied:   codes.PermissionDenied,
+	errors.CodeResourceExhausted:  codes.ResourceExhausted,
+	errors.CodeFailedPrecondition: codes.FailedPrecondition,
+	errors.CodeAborted:            codes.Aborted,
+	errors.CodeOutOfRange:         codes.OutOfRange,
+	errors.CodeUnimplemented:      codes.Unimplemented,
+	errors.CodeInternal:           codes.Internal,
+	errors.CodeUnavailable:        codes.Unavailable,
+	errors.CodeDataLoss:           codes.DataLoss,
+	errors.CodeUnauthenticated:    codes.Unauthenticated,
+}
+
+// Status converts err to a gRPC status. The fields of an *Error are packed into
+// the standard google.rpc detail messages, and its code becomes the status code.
+// Other errors become a status with code Unknown. Handlers can return
+// Status(err).Err().
+func Status(err error) *status.Status {
+	if err == nil {
+		return nil
+	}
+
+	var e *errors.Error
+	if !stderrors.As(err, &e) {
+		return status.New(codes.Unknown, err.Error())
+	}
+
+	code, ok := grpcCodes[e.Code()]
+	if !ok {
+		code = codes.Unknown
+	}
+	st := status.New(code, e.Error())
+
+	var details []protoadapt.MessageV1
+	if reason, domain, metadata := e.Reason(), e.Domain(), e.Metadata(); reason != nil || domain != nil || len(metadata) > 0 {
+		details = append(details, &errdetails.ErrorInfo{
+			Reason:   lo.FromPtr(reason),
+			Domain:   lo.FromPtr(domain),
+			Metadata: metadata,
+		})
+	}
+
+	if fieldViolations := e.FieldViolations(); len(fieldViolations) > 0 {
+		details = append(details, &errdetails.BadRequest{
+			FieldViolations: lo.Map(fieldViolations, func(v errors.FieldViolation, _ int) *errdetails.BadRequest_FieldViolation {
+				return &errdetails.BadRequest_FieldViolation{Field: v.Field, Description: v.Description}
+			}),
+		})
+	}
+
+	if quotaViolations := e.QuotaViolations(); len(quotaViolations) > 0 {
+		details = append(details, &errdetails.QuotaFailure{
+			Violations: lo.Map(quotaViolations, func(v errors.QuotaViolation, _ int) *errdetails.QuotaFailure_Violation {
+				return &errdetails.QuotaFailure_Violation{Subject: v.Subject, Description: v.Description}
+			}),
+		})
+	}
+
+	if preconditionViolations := e.PreconditionViolations(); len(preconditionViolations) > 0 {
+		details = append(details, &errdetails.PreconditionFailure{
+			Violations: lo.Map(preconditionViolations, func(v errors.PreconditionViolation, _ int) *errdetails.PreconditionFailure_Violation {
+				return &errdetails.PreconditionFailure_Violation{Type: v.Type, Subject: v.Subject, Description: v.Description}
+			}),
+		})
+	}
+
+	for _, l := range e.Localizations() {
+		details = append(details, &errdetails.LocalizedMessage{Locale: l.Locale, Message: l.Message})
+	}
+
+	if help := e.Help(); lo.IsNotEmpty(help) {
+		details = append(details, &errdetails.Help{
+			Links: []*errdetails.Help_Link{{Description: help.Description, Url: help.URL}},
+		})
+	}
+
+	if resource := e.Resource(); lo.IsNotEmpty(resource) {
+		details = append(details, &errdetails.ResourceInfo{
+			ResourceType: resource.Type,
+			ResourceName: resource.Name,
+			Owner:        resource.Owner,
+			Description:  resource.Description,
+		})
+	}
+
+	if requestID := e.RequestID(); requestID != nil {
+		details = append(details, &errdetails.RequestInfo{RequestId: *requestID})
+	}
+
+	if retry := e.Retry(); lo.IsNotEmpty(retry) {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(retry.Delay)})
+	}
+
+	if len(details) == 0 {
+		return st
+	}
+	withDetails, detailsErr := st.WithDetails(details...)
+	if detailsErr != nil {
+		return st
+	}
+	return withDetails
+}
+
+// DetailMetadataPrefix is prepended to the full name of the detail messages that
+// FromStatus cannot map to a field, to store them as metadata.
+const DetailMetadataPrefix = "grpc.detail."
+
+// FromStatus converts a gRPC status back to an *Error. It is the inverse of
+// Status: the standard google.rpc detail messages are unpacked into the
+// corresponding fields, and the other details are kept as metadata entries
+// holding their JSON encoding.
+func FromStatus(st *status.Status) *errors.Error {
+	if st == nil {
+		return nil
+	}
+
+	b := errors.NewBuilder()
+
+	for code, grpcCode := range grpcCodes {
+		if grpcCode == st.Code() {
+			b = b.Code(code)
+			break
+		}
+	}
+
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if d.GetReason() != "" {
+				b = b.Reason(d.GetReason())
+			}
+			if d.GetDomain() != "" {
+				b = b.Domain(d.GetDomain())
+			}
+			for k, v := range d.GetMetadata() {
+				b = b.WithMetadata(k, v)
+			}
+		case *errdetails.BadRequest:
+			for _, v := range d.GetFieldViolations() {
+				b = b.WithFieldViolation(v.GetField(), v.GetDescription())
+			}
+		case *errdetails.QuotaFailure:
+			for _, v := range d.GetViolations() {
+				b = b.WithQuotaViolation(v.GetSubject(), v.GetDescription())
+			}
+		case *errdetails.PreconditionFailure:
+			for _, v := range d.GetViolations() {
+				b = b.WithPreconditionViolation(v.GetType(), v.GetSubject(), v.GetDescription())
+			}
+		case *errdetails.LocalizedMessage:
+			b = b.WithLocalization(errors.Localization{Locale: d.GetLocale(), Message: d.GetMessage()})
+		case *errdetails.Help:
+			if link, ok := lo.First(d.GetLinks()); ok {
+				b = b.Help(errors.Help{Description: link.GetDescription(), URL: link.GetUrl()})
+			}
+		case *errdetails.ResourceInfo:
+			b = b.Resource(errors.Resource{
+				Type:        d.GetResourceType(),
+				Name:        d.GetResourceName(),
+				Owner:       d.GetOwner(),
+				Description: d.GetDescription(),
+			})
+		case *errdetails.RequestInfo:
+			b = b.RequestID(d.GetRequestId())
+		case *errdetails.RetryInfo:
+			b = b.Retry(errors.Retry{Delay: d.GetRetryDelay().AsDuration()})
+		case proto.Message:
+			if data, err := protojson.Marshal(d); err == nil {
+				b = b.WithMetadata(DetailMetadataPrefix+string(d.ProtoReflect().Descriptor().FullName()), string(data))
+			}
+		}
+	}
+
+	return b.Error(st.Message()).(*errors.Error)
+}
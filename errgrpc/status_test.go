@@ -0,0 +1,81 @@
+package errgrpc_test
+
+import (
+	"io/fs"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+
+	"github.com/notjustmoney/errors"
+	"github.com/notjustmoney/errors/errgrpc"
+)
+
+func TestStatus(t *testing.T) {
+	is := assert.New(t)
+
+	err := errors.
+		Reason("ERROR_REASON_INVALID_REFRESH_TOKEN").
+		Code(errors.CodeInvalidArgument).
+		Domain("identity").
+		WithMetadata("client", "web").
+		WithFieldViolation("refreshToken", "expired").
+		Retry(errors.Retry{Delay: time.Second}).
+		Error("invalid refresh token")
+
+	st := errgrpc.Status(err)
+	is.Equal(codes.InvalidArgument, st.Code())
+	is.Equal("invalid refresh token", st.Message())
+
+	details := st.Details()
+	is.Len(details, 3)
+	is.Equal("ERROR_REASON_INVALID_REFRESH_TOKEN", details[0].(*errdetails.ErrorInfo).GetReason())
+	is.Equal("identity", details[0].(*errdetails.ErrorInfo).GetDomain())
+	is.Equal(map[string]string{"client": "web"}, details[0].(*errdetails.ErrorInfo).GetMetadata())
+	is.Equal("refreshToken", details[1].(*errdetails.BadRequest).GetFieldViolations()[0].GetField())
+	is.Equal(time.Second, details[2].(*errdetails.RetryInfo).GetRetryDelay().AsDuration())
+
+	is.Equal(codes.Unknown, errgrpc.Status(fs.ErrNotExist).Code())
+	is.Nil(errgrpc.Status(nil))
+}
+
+func TestFromStatus(t *testing.T) {
+	is := assert.New(t)
+
+	var want *errors.Error
+	is.ErrorAs(
+		errors.
+			Reason("ERROR_REASON_INVALID_REFRESH_TOKEN").
+			Code(errors.CodeInvalidArgument).
+			Domain("identity").
+			WithMetadata("client", "web").
+			WithFieldViolation("refreshToken", "expired").
+			WithPreconditionViolation("TOS", "user/42", "terms of service not accepted").
+			Resource(errors.Resource{Type: "token", Name: "refresh"}).
+			Error("invalid refresh token"),
+		&want,
+	)
+
+	st, err := errgrpc.Status(want).WithDetails(&errdetails.DebugInfo{Detail: "debug"})
+	is.NoError(err)
+
+	got := errgrpc.FromStatus(st)
+	is.Equal(want.Error(), got.Error())
+	is.Equal(want.Code(), got.Code())
+	is.Equal(want.Reason(), got.Reason())
+	is.Equal(want.Domain(), got.Domain())
+	is.Equal(want.FieldViolations(), got.FieldViolations())
+	is.Equal(want.PreconditionViolations(), got.PreconditionViolations())
+	is.Equal("TOS", got.PreconditionViolations()[0].Type)
+	is.Equal(want.Resource(), got.Resource())
+	is.Equal(map[string]string{
+		"client":                           "web",
+		"grpc.detail.google.rpc.DebugInfo": `{"detail":"debug"}`,
+	}, got.Metadata())
+	is.False(got.Time().IsZero())
+	is.NotEmpty(got.StackTrace())
+
+	is.Nil(errgrpc.FromStatus(nil))
+}
@@ -0,0 +1,78 @@
+// Package errhttp writes errors as net/http responses.
+package errhttp
+
+import (
+	stderrors "errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/notjustmoney/errors"
+)
+
+type options struct {
+	stackTrace      bool
+	wwwAuthenticate string
+}
+
+// Option configures WriteHTTP.
+type Option func(*options)
+
+// WithStackTrace includes the stack traces in the response body. They are
+// omitted by default, as they leak the server layout.
+func WithStackTrace() Option {
+	return func(o *options) {
+		o.stackTrace = true
+	}
+}
+
+// WithWWWAuthenticate sets the WWW-Authenticate header written along with
+// 401 responses, e.g. `Bearer realm="api"`.
+func WithWWWAuthenticate(challenge string) Option {
+	return func(o *options) {
+		o.wwwAuthenticate = challenge
+	}
+}
+
+// genericBody is written for errors that are not *errors.Error, whose message
+// may not be safe to expose.
+var genericBody = []byte(`{"code":"INTERNAL","message":"internal server error"}`)
+
+// WriteHTTP writes err as a JSON response. An *errors.Error is written with
+// its HTTP status and its JSON encoding, without stack traces unless
+// WithStackTrace is given. Unauthenticated errors get the WWW-Authenticate
+// header if configured, and errors with a retry delay get a Retry-After header.
+// Any other error is written as a generic 500 response.
+func WriteHTTP(w http.ResponseWriter, err error, opts ...Option) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var e *errors.Error
+	if !stderrors.As(err, &e) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(genericBody)
+		return
+	}
+
+	body, marshalErr := e.MarshalJSONWith(errors.MarshalOptions{OmitStackTrace: !o.stackTrace})
+	if marshalErr != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(genericBody)
+		return
+	}
+
+	status := e.HTTPStatus()
+	if status == http.StatusUnauthorized && o.wwwAuthenticate != "" {
+		w.Header().Set("WWW-Authenticate", o.wwwAuthenticate)
+	}
+	if delay := e.Retry().Delay; delay > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+	}
+
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,62 @@
+package errhttp_test
+
+import (
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/notjustmoney/errors"
+	"github.com/notjustmoney/errors/errhttp"
+)
+
+func TestWriteHTTP(t *testing.T) {
+	is := assert.New(t)
+
+	rec := httptest.NewRecorder()
+	errhttp.WriteHTTP(rec, errors.
+		Reason("ERROR_REASON_INVALID_ARGUMENT").
+		HTTPStatus(http.StatusBadRequest).
+		Error("invalid argument"))
+
+	is.Equal(http.StatusBadRequest, rec.Code)
+	is.Equal("application/json", rec.Header().Get("Content-Type"))
+
+	var body map[string]any
+	is.NoError(json.Unmarshal(rec.Body.Bytes(), &body))
+	is.Equal("ERROR_REASON_INVALID_ARGUMENT", body["reason"])
+	is.NotContains(body, "stackTrace")
+
+	rec = httptest.NewRecorder()
+	errhttp.WriteHTTP(rec, errors.New("invalid argument"), errhttp.WithStackTrace())
+	is.NoError(json.Unmarshal(rec.Body.Bytes(), &body))
+	is.Contains(body, "stackTrace")
+}
+
+func TestWriteHTTPOpaqueError(t *testing.T) {
+	is := assert.New(t)
+
+	rec := httptest.NewRecorder()
+	errhttp.WriteHTTP(rec, fs.ErrNotExist)
+
+	is.Equal(http.StatusInternalServerError, rec.Code)
+	is.JSONEq(`{"code":"INTERNAL","message":"internal server error"}`, rec.Body.String())
+}
+
+func TestWriteHTTPHeaders(t *testing.T) {
+	is := assert.New(t)
+
+	rec := httptest.NewRecorder()
+	errhttp.WriteHTTP(rec, errors.Unauthenticated("missing token"), errhttp.WithWWWAuthenticate(`Bearer realm="api"`))
+	is.Equal(http.StatusUnauthorized, rec.Code)
+	is.Equal(`Bearer realm="api"`, rec.Header().Get("WWW-Authenticate"))
+
+	rec = httptest.NewRecorder()
+	errhttp.WriteHTTP(rec, errors.ResourceExhausted("requests", "rate limit exceeded", 1500*time.Millisecond))
+	is.Equal(http.StatusTooManyRequests, rec.Code)
+	is.Equal("2", rec.Header().Get("Retry-After"))
+}
@@ -4,21 +4,39 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"runtime"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/samber/lo"
 )
 
+// lazyState holds the values of an Error that are computed on first read. Each
+// Error has its own, so that reading one error never writes into another.
+type lazyState struct {
+	mu                 sync.Mutex
+	trace              *string
+	span               *string
+	renderedStackTrace *string
+}
+
 type Error struct {
 	err     error
 	message *string
+	// messageFromCause is set by Errorf, whose message is the text of its cause.
+	messageFromCause bool
 
 	// error information
-	reason   *string
-	domain   *string
-	metadata map[string]string
+	code       Code
+	severity   Severity
+	httpStatus *int
+	reason     *string
+	domain     *string
+	metadata   map[string]string
+	attributes map[string]any
 
 	// failure
 	quotaViolations        []QuotaViolation
@@ -30,72 +48,198 @@ type Error struct {
 	tenantID *string
 
 	// tracing
-	trace     *string
-	span      *string
-	requestID *string
-	tags      []string
-	time      time.Time
+	trace        *string
+	span         *string
+	spanKind     SpanKind
+	spanOnDemand bool
+	requestID    *string
+	tags         []string
+	time         time.Time
 
 	// guidance
-	help          Help
-	resource      Resource
-	localizations []Localization
-	retry         Retry
+	help            Help
+	resource        Resource
+	localizations   []Localization
+	localizationKey *string
+	retry           Retry
+
+	// reporting
+	reported bool
+
+	// copyOf is the error this one was copied from by the functions returning
+	// a modified copy of a chain, such as Policy.Apply, so that the copy still
+	// matches it with Is.
+	copyOf *Error
 
 	// debug
-	stackTrace stackTrace
+	stackTrace         stackTrace
+	importedStackTrace *string
+	source             *stackTraceFrame
+	stackDepth         *int
+	skipCallers        int
+	goroutineID        uint64
+
+	lazy *lazyState
 }
 
-// Error returns the error message.
+// Error returns the error message. The message and the cause are joined with
+// ": ", unless the error was built with Errorf, whose message is the cause's own
+// text.
 func (e *Error) Error() string {
-	var sb strings.Builder
-	if e.message != nil {
-		sb.WriteString(*e.message)
-		if e.err != nil {
-			sb.WriteString(": ")
-		}
+	if e.err == nil {
+		return lo.FromPtr(e.message)
 	}
-	if e.err != nil {
-		sb.WriteString(e.err.Error())
+
+	cause := e.err.Error()
+	if e.message == nil || e.messageFromCause {
+		return cause
 	}
 
-	return sb.String()
+	return *e.message + ": " + cause
 }
 
 func (e *Error) Unwrap() error {
+	if e == nil {
+		return nil
+	}
 	return e.err
 }
 
+// Root returns the innermost error of the chain, following Unwrap until it
+// returns nil. It may be an error that is not an *Error, and is e itself when e
+// has no cause. The branches of joined errors are not followed.
+func (e *Error) Root() error {
+	var err error = e
+	for {
+		next := errors.Unwrap(err)
+		if next == nil {
+			return err
+		}
+		err = next
+	}
+}
+
+// Chain returns every *Error of the chain, from the outermost to the innermost,
+// to inspect the fields each layer contributed. The branches of joined errors
+// follow each other in order. The accessors of a layer still resolve through
+// its cause; call Detach on it to read only the fields it was built with.
+func (e *Error) Chain() []*Error {
+	var chain []*Error
+	recursive(e, func(e *Error) {
+		chain = append(chain, e)
+	})
+	return chain
+}
+
+// Detach returns a copy of the error without its cause, so that nothing behind
+// it can be matched with Is or As, e.g. when crossing a trust boundary. The
+// fields of the error itself are kept and the original is not modified.
+func (e *Error) Detach() *Error {
+	e2 := e.clone()
+	e2.err = nil
+	return (*Error)(&e2)
+}
+
+// Clone returns a deep copy of the error, e.g. to specialize a base error per
+// request. The fields of the error itself, such as its metadata and tags, are
+// copied, while the cause and the stack trace are shared.
+func (e *Error) Clone() *Error {
+	e2 := e.clone()
+	return (*Error)(&e2)
+}
+
+// WithoutStack returns a copy of the error with the stack traces of every layer
+// of the chain removed, so that StackTrace, Sources and MarshalJSON expose no
+// frames, e.g. for responses sent to clients. The copy reports the same trace
+// and span IDs as the original, which is not modified.
+func (e *Error) WithoutStack() *Error {
+	return mapChain(e, func(e ErrorBuilder) ErrorBuilder {
+		e.stackTrace = nil
+		e.importedStackTrace = nil
+		e.source = nil
+		return e
+	})
+}
+
+// Is reports whether err is e itself, or the error e is a modified copy of.
+// When err is an *Error with a reason and no cause, such as a sentinel declared
+// with Reason(...).Build(), e also matches it if it has the same reason. The
+// cause is not matched here: errors.Is already follows it through Unwrap, and
+// matching it again on every layer made the walk grow exponentially with the
+// depth of the chain.
 func (e *Error) Is(err error) bool {
-	if errors.Is(e.err, err) {
+	if t, ok := err.(*Error); ok && isReasonSentinel(t) && e != nil && e.reason != nil && *e.reason == *t.reason {
+		return true
+	}
+	for ; e != nil; e = e.copyOf {
+		if e == err {
+			return true
+		}
+	}
+	return false
+}
+
+// As assigns e to target if target is a **Error, so that errors.As always
+// yields the outermost *Error. Other targets are left to errors.As, which
+// already follows the cause through Unwrap, as for Is.
+func (e *Error) As(target any) bool {
+	if t, ok := target.(**Error); ok {
+		*t = e
 		return true
 	}
-	return e == err
+	return false
 }
 
+// StackTrace returns the stack traces of the chain, from the innermost error
+// out. It is rendered on first use and cached, so the stack trace settings in
+// effect at that time apply.
 func (e *Error) StackTrace() string {
+	if e.lazy == nil {
+		return e.renderStackTrace()
+	}
+
+	e.lazy.mu.Lock()
+	defer e.lazy.mu.Unlock()
+	if e.lazy.renderedStackTrace == nil {
+		st := e.renderStackTrace()
+		e.lazy.renderedStackTrace = &st
+	}
+	return *e.lazy.renderedStackTrace
+}
+
+func (e *Error) renderStackTrace() string {
 	var (
 		blocks   []string
 		topFrame stackTraceFrame
 	)
 	recursive(e, func(ee *Error) {
+		if len(ee.stackTrace) == 0 && ee.importedStackTrace == nil {
+			return
+		}
+
+		var message string
+		if ee.message != nil {
+			message = *ee.message
+		} else {
+			message = coalesceOrEmpty(
+				lo.TernaryF(
+					ee.err != nil,
+					func() string { return ee.err.Error() },
+					func() string { return "" }),
+				"Error",
+			)
+		}
 		if len(ee.stackTrace) > 0 {
-			var message string
-			if ee.message != nil {
-				message = *ee.message
-			} else {
-				message = coalesceOrEmpty(
-					lo.TernaryF(
-						ee.err != nil,
-						func() string { return ee.err.Error() },
-						func() string { return "" }),
-					"Error",
-				)
-			}
 			block := fmt.Sprintf("%s\n%s", message, ee.stackTrace.StringUntilFrame(topFrame))
 			blocks = append([]string{block}, blocks...)
 			topFrame = (ee.stackTrace)[0]
 		}
+		// The imported stack trace was captured where the error originally
+		// occurred, so it is deeper than the frames captured in this process.
+		if ee.importedStackTrace != nil {
+			block := fmt.Sprintf("%s\n%s", message, *ee.importedStackTrace)
+			blocks = append([]string{block}, blocks...)
+		}
 	})
 
 	if len(blocks) == 0 {
@@ -105,6 +249,110 @@ func (e *Error) StackTrace() string {
 	return "Error: " + strings.Join(blocks, "\nThrown: ")
 }
 
+// Frames returns the stack frames of the whole chain, in the order StackTrace
+// renders them: the frames of an outer error are trimmed from the point where
+// they join the stack of the error it wraps. Imported stack traces have no
+// frames.
+func (e *Error) Frames() []Frame {
+	var (
+		blocks   [][]Frame
+		topFrame stackTraceFrame
+	)
+	recursive(e, func(ee *Error) {
+		if len(ee.stackTrace) > 0 {
+			blocks = append([][]Frame{ee.stackTrace.FramesUntilFrame(topFrame)}, blocks...)
+			topFrame = ee.stackTrace[0]
+		}
+	})
+
+	return lo.Flatten(blocks)
+}
+
+// RuntimeFrames resolves the program counters captured by the innermost error
+// of the chain with runtime.CallersFrames, which preserves inlining
+// information. Frames set with Source have no program counter and are left
+// out.
+func (e *Error) RuntimeFrames() []runtime.Frame {
+	var innermost stackTrace
+	recursive(e, func(e *Error) {
+		if len(e.stackTrace) > 0 {
+			innermost = e.stackTrace
+		}
+	})
+
+	var pcs []uintptr
+	for _, frame := range innermost {
+		if frame.pc != 0 {
+			// CallersFrames expects return addresses, while runtime.Caller
+			// reports the address of the call instruction.
+			pcs = append(pcs, frame.pc+1)
+		}
+	}
+	if len(pcs) == 0 {
+		return nil
+	}
+
+	var frames []runtime.Frame
+	callersFrames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := callersFrames.Next()
+		frames = append(frames, frame)
+		if !more {
+			break
+		}
+	}
+	return frames
+}
+
+// Origin returns the location where the innermost error of the chain was
+// thrown. The zero values are returned when no stack trace was captured.
+func (e *Error) Origin() (file string, line int, function string) {
+	var origin *stackTraceFrame
+	recursive(e, func(e *Error) {
+		if len(e.stackTrace) > 0 {
+			origin = &e.stackTrace[0]
+		}
+	})
+
+	if origin == nil {
+		return "", 0, ""
+	}
+	return redactStackPath(origin.file), origin.line, origin.function
+}
+
+// Summary returns a one-line summary of the error, like
+// "reason (domain): message @ file:line". Missing parts are omitted.
+func (e *Error) Summary() string {
+	var sb strings.Builder
+	if reason := e.Reason(); reason != nil {
+		sb.WriteString(*reason)
+	}
+	if domain := e.Domain(); domain != nil {
+		if sb.Len() > 0 {
+			sb.WriteString(" ")
+		}
+		sb.WriteString("(")
+		sb.WriteString(*domain)
+		sb.WriteString(")")
+	}
+	if message := e.Error(); message != "" {
+		if sb.Len() > 0 {
+			sb.WriteString(": ")
+		}
+		sb.WriteString(message)
+	}
+	if file, line, _ := e.Origin(); file != "" {
+		if sb.Len() > 0 {
+			sb.WriteString(" ")
+		}
+		sb.WriteString("@ ")
+		sb.WriteString(file)
+		sb.WriteString(":")
+		sb.WriteString(strconv.Itoa(line))
+	}
+	return sb.String()
+}
+
 // Sources returns the source fragments of the error.
 func (e *Error) Sources() string {
 	var blocks [][]string
@@ -145,16 +393,91 @@ func (e *Error) Message() *string {
 	})
 }
 
+// Code returns the canonical code of the error, or CodeUnknown if it has none.
+func (e *Error) Code() Code {
+	code := recursiveAttr(e, func(e *Error) Code {
+		return e.code
+	})
+	return coalesceOrEmpty(code, CodeUnknown)
+}
+
+// Severity returns the severity of the error, or SeverityError if it has none.
+func (e *Error) Severity() Severity {
+	return coalesceOrEmpty(e.explicitSeverity(), SeverityError)
+}
+
+func (e *Error) explicitSeverity() Severity {
+	return recursiveAttr(e, func(e *Error) Severity {
+		return e.severity
+	})
+}
+
+// HTTPStatus returns the HTTP status set on the error, or else the status its
+// code maps to, which is 500 if it has no code.
+func (e *Error) HTTPStatus() int {
+	if status := e.explicitHTTPStatus(); status != nil {
+		return *status
+	}
+	return e.Code().HTTPStatus()
+}
+
+func (e *Error) explicitHTTPStatus() *int {
+	return recursiveAttr(e, func(e *Error) *int {
+		return e.httpStatus
+	})
+}
+
 func (e *Error) Reason() *string {
 	return recursiveAttr(e, func(e *Error) *string {
 		return e.reason
 	})
 }
 
+// HasReason reports whether any layer of the chain, including the branches of
+// joined errors, has the given reason.
+func (e *Error) HasReason(reason string) bool {
+	return e != nil && hasReason(e, reason)
+}
+
+// ReasonCounts walks the whole error tree, including the branches of joined
+// errors, and counts the reasons. Every linear segment of the tree contributes
+// the reason it resolves to, so a branch wrapped several times is counted once.
+func (e *Error) ReasonCounts() map[string]int {
+	counts := map[string]int{}
+	countReasons(e, counts, map[*Error]bool{})
+	return counts
+}
+
+// Domain returns the domain of the error. When SetInferDomainFromReason is
+// enabled and no domain is set, the domain is inferred from a namespaced reason.
 func (e *Error) Domain() *string {
-	return recursiveAttr(e, func(e *Error) *string {
+	domain := recursiveAttr(e, func(e *Error) *string {
 		return e.domain
 	})
+	if domain != nil || !inferDomainFromReason.Load() {
+		return domain
+	}
+
+	if reason := e.Reason(); reason != nil {
+		if prefix, _, ok := strings.Cut(*reason, "."); ok && prefix != "" {
+			return &prefix
+		}
+	}
+	return nil
+}
+
+// HasDomain reports whether any layer of the chain has the given domain, or the
+// domain resolved by Domain is the given one.
+func (e *Error) HasDomain(domain string) bool {
+	var found bool
+	recursive(e, func(e *Error) {
+		found = found || (e.domain != nil && *e.domain == domain)
+	})
+	if found {
+		return true
+	}
+	resolved := e.Domain()
+	return resolved != nil && *resolved == domain
 }
 
 func (e *Error) Metadata() map[string]string {
@@ -163,37 +486,208 @@ func (e *Error) Metadata() map[string]string {
 	})
 }
 
+// GetMetadata returns the metadata value of key, with the semantics of
+// MergedMetadata but without building the map: the outermost layer that sets
+// key wins.
+func (e *Error) GetMetadata(key string) (string, bool) {
+	var (
+		value string
+		found bool
+	)
+	recursive(e, func(e *Error) {
+		if !found {
+			value, found = e.metadata[key]
+		}
+	})
+	return value, found
+}
+
+// Attributes returns the typed metadata set with WithAttribute.
+func (e *Error) Attributes() map[string]any {
+	return recursiveAttr(e, func(e *Error) map[string]any {
+		return e.attributes
+	})
+}
+
+// MergedMetadata returns the metadata of every layer of the chain combined. On
+// a key set by several layers, the outermost layer wins, as it has the most
+// context on the failure.
+func (e *Error) MergedMetadata() map[string]string {
+	var merged map[string]string
+	recursive(e, func(e *Error) {
+		for key, value := range e.metadata {
+			if merged == nil {
+				merged = map[string]string{}
+			}
+			if _, ok := merged[key]; !ok {
+				merged[key] = value
+			}
+		}
+	})
+	return merged
+}
+
 func (e *Error) QuotaViolations() []QuotaViolation {
-	return recursiveAttr(e, func(e *Error) []QuotaViolation {
+	return recursiveSlice(e, func(e *Error) []QuotaViolation {
 		return e.quotaViolations
 	})
 }
 
 func (e *Error) PreconditionViolations() []PreconditionViolation {
-	return recursiveAttr(e, func(e *Error) []PreconditionViolation {
+	return recursiveSlice(e, func(e *Error) []PreconditionViolation {
 		return e.preconditionViolations
 	})
 }
 
 func (e *Error) FieldViolations() []FieldViolation {
-	return recursiveAttr(e, func(e *Error) []FieldViolation {
+	return recursiveSlice(e, func(e *Error) []FieldViolation {
 		return e.fieldViolations
 	})
 }
 
-func (e *Error) Trace() *string {
-	trace := recursiveAttr(e, func(e *Error) *string {
-		return e.trace
+// AllQuotaViolations returns the quota violations of every layer of the chain,
+// from the outermost, without duplicates. QuotaViolations only returns those
+// of the innermost layer.
+func (e *Error) AllQuotaViolations() []QuotaViolation {
+	return recursiveUniq(e, func(e *Error) []QuotaViolation {
+		return e.quotaViolations
+	})
+}
+
+// AllPreconditionViolations returns the precondition violations of every layer
+// of the chain, from the outermost, without duplicates.
+func (e *Error) AllPreconditionViolations() []PreconditionViolation {
+	return recursiveUniq(e, func(e *Error) []PreconditionViolation {
+		return e.preconditionViolations
 	})
-	return lo.If(trace != nil, trace).ElseF(func() *string {
-		traceID := uuid.NewString() // TODO: use a sortable unique identifier(ref: https://github.com/oklog/ulid)
-		e.trace = &traceID
-		return &traceID
+}
+
+// AllFieldViolations returns the field violations of every layer of the chain,
+// from the outermost, without duplicates.
+func (e *Error) AllFieldViolations() []FieldViolation {
+	return recursiveUniq(e, func(e *Error) []FieldViolation {
+		return e.fieldViolations
+	})
+}
+
+// FieldViolationMap returns the descriptions of the field violations grouped by
+// field.
+func (e *Error) FieldViolationMap() map[string][]string {
+	fieldViolations := e.FieldViolations()
+	if len(fieldViolations) == 0 {
+		return nil
+	}
+
+	return lo.FromPairs(lo.Map(
+		groupFieldViolations(fieldViolations),
+		func(group lo.Tuple2[string, []string], _ int) lo.Entry[string, []string] {
+			return lo.Entry[string, []string]{Key: group.A, Value: group.B}
+		},
+	))
+}
+
+func (e *Error) UserID() *string {
+	return recursiveAttr(e, func(e *Error) *string {
+		return e.userID
+	})
+}
+
+func (e *Error) TenantID() *string {
+	return recursiveAttr(e, func(e *Error) *string {
+		return e.tenantID
 	})
 }
 
+// Trace returns the trace ID of the error, the one set on the innermost layer
+// of the chain that has one. When none was set, a random one is generated on
+// first read and kept on e, so that it is stable without being shared with the
+// other errors wrapping the same cause. It is safe for concurrent use.
+func (e *Error) Trace() *string {
+	if trace := recursiveSet(e, func(e *Error) *string {
+		return e.trace
+	}); trace != nil {
+		return trace
+	}
+	return e.generatedID(lazyTrace, true)
+}
+
+// loadTrace returns the trace ID set on e itself, or the one generated for it,
+// without generating one.
+func (e *Error) loadTrace() *string {
+	if e.trace != nil {
+		return e.trace
+	}
+	return e.generatedID(lazyTrace, false)
+}
+
+// generatedID returns the ID generated for e in the field of its lazy state
+// selected by id, generating it first if generate is set. A copy made by
+// WithoutStack, Redacted or Policy.Apply uses the IDs of the error it was
+// copied from, so that both report the same ones whichever is read first.
+func (e *Error) generatedID(id func(*lazyState) **string, generate bool) *string {
+	for e.copyOf != nil {
+		e = e.copyOf
+	}
+	if e.lazy == nil {
+		if !generate {
+			return nil
+		}
+		value := newID()
+		return &value
+	}
+
+	e.lazy.mu.Lock()
+	defer e.lazy.mu.Unlock()
+	field := id(e.lazy)
+	if *field == nil && generate {
+		value := newID()
+		*field = &value
+	}
+	return *field
+}
+
+func lazyTrace(l *lazyState) **string {
+	return &l.trace
+}
+
+func lazySpan(l *lazyState) **string {
+	return &l.span
+}
+
+// Span returns the span ID of the error, the one set on the innermost layer of
+// the chain that has one. When none was set and the chain ends with a Wrap of an
+// error that is not an *Error, one is generated on first read and kept on e, so
+// that the errors wrapping the same cause each get their own. It is safe for
+// concurrent use.
 func (e *Error) Span() *string {
-	return e.span
+	if span := recursiveSet(e, func(e *Error) *string {
+		return e.span
+	}); span != nil {
+		return span
+	}
+
+	innermost := recursiveAttr(e, func(e *Error) *Error {
+		return e
+	})
+	if innermost == nil || !innermost.spanOnDemand {
+		return nil
+	}
+	return e.generatedID(lazySpan, true)
+}
+
+// loadSpan returns the span ID set on e itself, or the one generated for it,
+// without generating one.
+func (e *Error) loadSpan() *string {
+	if e.span != nil {
+		return e.span
+	}
+	return e.generatedID(lazySpan, false)
+}
+
+func (e *Error) SpanKind() SpanKind {
+	return recursiveAttr(e, func(e *Error) SpanKind {
+		return e.spanKind
+	})
 }
 
 func (e *Error) RequestID() *string {
@@ -207,25 +701,24 @@ func (e *Error) Tags() []string {
 		tags = append(tags, e.tags...)
 	})
 
-	return lo.Uniq(tags)
+	tags = lo.Uniq(tags)
+	if getTagSortMode() == TagSortAlphabetical {
+		slices.Sort(tags)
+	}
+	return tags
+}
+
+// HasTag reports whether tag is one of Tags.
+func (e *Error) HasTag(tag string) bool {
+	return slices.Contains(e.Tags(), tag)
 }
 
+// Time returns the time the innermost error of the chain was built at. It is
+// captured by the builder and never changes afterwards.
 func (e *Error) Time() time.Time {
-	t := recursiveAttr(e, func(e *Error) time.Time {
+	return recursiveAttr(e, func(e *Error) time.Time {
 		return e.time
 	})
-
-	return lo.TernaryF(
-		t.IsZero(),
-		func() time.Time {
-			now := time.Now()
-			e.time = now
-			return now
-		},
-		func() time.Time {
-			return t
-		},
-	)
 }
 
 func (e *Error) Help() Help {
@@ -246,20 +739,90 @@ func (e *Error) Localizations() []Localization {
 	})
 }
 
+func (e *Error) LocalizationKey() *string {
+	return recursiveAttr(e, func(e *Error) *string {
+		return e.localizationKey
+	})
+}
+
+// LocalizedMessage returns the message for the given locale as resolved by
+// Localize. If there is no match, the reason is returned, or the error message
+// if no reason is set.
+func (e *Error) LocalizedMessage(locale string) string {
+	if message, ok := e.Localize(locale); ok {
+		return message
+	}
+
+	if reason := e.Reason(); reason != nil {
+		return *reason
+	}
+
+	return e.Error()
+}
+
 func (e *Error) Retry() Retry {
 	return recursiveAttr(e, func(e *Error) Retry {
 		return e.retry
 	})
 }
 
+// ShouldRetry reports whether the operation should be attempted again, i.e.
+// whether fewer attempts than the maximum of the retry policy were made. It is
+// false when the policy has no maximum.
+func (e *Error) ShouldRetry() bool {
+	retry := e.Retry()
+	return retry.Attempt < retry.MaxAttempts
+}
+
+// IsReported reports whether any layer of the chain was marked as reported to
+// an incident system. The flag is advisory: nothing in this package acts on it.
+func (e *Error) IsReported() bool {
+	var reported bool
+	recursive(e, func(e *Error) {
+		reported = reported || e.reported
+	})
+	return reported
+}
+
+// GoroutineID returns the ID of the goroutine that created the innermost error
+// of the chain, or 0 if it was not captured. It is only captured after
+// SetCaptureGoroutineID(true), and is best-effort: the runtime does not
+// guarantee the format it is parsed from, and IDs are reused once goroutines
+// exit.
+func (e *Error) GoroutineID() uint64 {
+	var goroutineID uint64
+	recursive(e, func(e *Error) {
+		if e.goroutineID != 0 {
+			goroutineID = e.goroutineID
+		}
+	})
+	return goroutineID
+}
+
 func (e *Error) LogValue() slog.Value {
 	if e == nil {
 		return slog.GroupValue()
 	}
 
+	if redactInLogValue.Load() {
+		e = e.Redacted()
+	}
+
 	var attrs []slog.Attr
 	if message := e.Message(); message != nil {
-		attrs = append(attrs, slog.String("message", *e.message))
+		attrs = append(attrs, slog.String("message", *message))
+	}
+
+	if code := e.Code(); code != CodeUnknown {
+		attrs = append(attrs, slog.String("code", code.String()))
+	}
+
+	if severity := e.explicitSeverity(); severity != 0 {
+		attrs = append(attrs, slog.Any("severity", severity.Level()))
+	}
+
+	if httpStatus := e.explicitHTTPStatus(); httpStatus != nil {
+		attrs = append(attrs, slog.Int("httpStatus", *httpStatus))
 	}
 
 	if reason := e.Reason(); reason != nil {
@@ -270,17 +833,18 @@ func (e *Error) LogValue() slog.Value {
 		attrs = append(attrs, slog.String("domain", *domain))
 	}
 
-	if len(e.metadata) > 0 {
-		attrs = append(attrs,
-			slog.Group(
-				"metadata",
-				lo.ToAnySlice(
-					lo.MapToSlice(e.metadata, func(k string, v string) slog.Attr {
-						return slog.String(k, v)
-					}),
-				)...,
-			),
-		)
+	if len(e.metadata) > 0 || len(e.attributes) > 0 {
+		metadata := lo.MapToSlice(e.metadata, func(k string, v string) slog.Attr {
+			return slog.String(k, truncateMetadataValue(v))
+		})
+		// A key set with both WithMetadata and WithAttribute keeps its
+		// metadata value.
+		for k, v := range e.attributes {
+			if _, ok := e.metadata[k]; !ok {
+				metadata = append(metadata, slog.Any(k, v))
+			}
+		}
+		attrs = append(attrs, slog.Group("metadata", lo.ToAnySlice(metadata)...))
 	}
 
 	if quotaViolations := e.QuotaViolations(); len(quotaViolations) > 0 {
@@ -325,6 +889,10 @@ func (e *Error) LogValue() slog.Value {
 		attrs = append(attrs, slog.String("span", *span))
 	}
 
+	if spanKind := e.SpanKind(); spanKind != SpanKindUnspecified {
+		attrs = append(attrs, slog.String("spanKind", spanKind.String()))
+	}
+
 	if requestID := e.RequestID(); requestID != nil {
 		attrs = append(attrs, slog.String("requestId", *requestID))
 	}
@@ -362,11 +930,36 @@ func (e *Error) LogValue() slog.Value {
 		))
 	}
 
+	if localizationKey := e.LocalizationKey(); localizationKey != nil {
+		attrs = append(attrs, slog.String("localizationKey", *localizationKey))
+	}
+
 	if retry := e.Retry(); lo.IsNotEmpty(retry) {
-		attrs = append(attrs, slog.Group(
-			"retry",
-			slog.String("delay", retry.Delay.String()),
-		))
+		retryAttrs := []any{slog.String("delay", retry.Delay.String())}
+		if retry.MaxAttempts != 0 {
+			retryAttrs = append(retryAttrs, slog.Int("maxAttempts", retry.MaxAttempts))
+		}
+		if retry.Attempt != 0 {
+			retryAttrs = append(retryAttrs, slog.Int("attempt", retry.Attempt))
+		}
+		if retry.Strategy != BackoffConstant {
+			retryAttrs = append(retryAttrs, slog.String("strategy", retry.Strategy.String()))
+		}
+		if retry.Multiplier != 0 {
+			retryAttrs = append(retryAttrs, slog.Float64("multiplier", retry.Multiplier))
+		}
+		if retry.MaxDelay != 0 {
+			retryAttrs = append(retryAttrs, slog.String("maxDelay", retry.MaxDelay.String()))
+		}
+		attrs = append(attrs, slog.Group("retry", retryAttrs...))
+	}
+
+	if e.IsReported() {
+		attrs = append(attrs, slog.Bool("reported", true))
+	}
+
+	if goroutineID := e.GoroutineID(); goroutineID != 0 {
+		attrs = append(attrs, slog.Uint64("goroutineId", goroutineID))
 	}
 
 	if st := e.StackTrace(); st != "" {
@@ -377,19 +970,91 @@ func (e *Error) LogValue() slog.Value {
 }
 
 func (e *Error) Format(s fmt.State, verb rune) {
-	if verb == 'v' && s.Flag('+') {
+	if verb == 'v' && s.Flag('#') {
+		fmt.Fprint(s, e.GoString())
+	} else if verb == 'v' && s.Flag('+') {
 		fmt.Fprint(s, e.formatVerbose())
 	} else {
 		fmt.Fprint(s, e.formatSummary())
 	}
 }
 
+// GoString returns a compact, Go-like representation of the populated fields of
+// the error as resolved through the chain, with the violations counted, for
+// %#v in tests and debugging:
+//
+//	&errors.Error{Message:"invalid refresh token", Reason:"ERROR_REASON_INVALID_REFRESH_TOKEN", FieldViolations:1}
+func (e *Error) GoString() string {
+	if e == nil {
+		return "(*errors.Error)(nil)"
+	}
+
+	var fields []string
+	field := func(name string, format string, value any) {
+		fields = append(fields, name+":"+fmt.Sprintf(format, value))
+	}
+
+	field("Message", "%q", e.Error())
+	if code := e.Code(); code != CodeUnknown {
+		field("Code", "%q", code.String())
+	}
+	if reason := e.Reason(); reason != nil {
+		field("Reason", "%q", *reason)
+	}
+	if domain := e.Domain(); domain != nil {
+		field("Domain", "%q", *domain)
+	}
+	if metadata := e.MergedMetadata(); len(metadata) > 0 {
+		field("Metadata", "%#v", metadata)
+	}
+	if tags := e.Tags(); len(tags) > 0 {
+		field("Tags", "%#v", tags)
+	}
+	if n := len(e.QuotaViolations()); n > 0 {
+		field("QuotaViolations", "%d", n)
+	}
+	if n := len(e.PreconditionViolations()); n > 0 {
+		field("PreconditionViolations", "%d", n)
+	}
+	if n := len(e.FieldViolations()); n > 0 {
+		field("FieldViolations", "%d", n)
+	}
+
+	return "&errors.Error{" + strings.Join(fields, ", ") + "}"
+}
+
 func (e *Error) formatVerbose() string {
 	var sb strings.Builder
 	sb.WriteString("Error: ")
 	sb.WriteString(e.Error())
 	sb.WriteString("\n")
 
+	if verboseShowCauseType.Load() {
+		if cause := foreignCause(e); cause != nil {
+			sb.WriteString("CauseType: ")
+			sb.WriteString(fmt.Sprintf("%T", cause))
+			sb.WriteString("\n")
+		}
+	}
+
+	if code := e.Code(); code != CodeUnknown {
+		sb.WriteString("Code: ")
+		sb.WriteString(code.String())
+		sb.WriteString("\n")
+	}
+
+	if severity := e.explicitSeverity(); severity != 0 {
+		sb.WriteString("Severity: ")
+		sb.WriteString(severity.String())
+		sb.WriteString("\n")
+	}
+
+	if httpStatus := e.explicitHTTPStatus(); httpStatus != nil {
+		sb.WriteString("HTTPStatus: ")
+		sb.WriteString(strconv.Itoa(*httpStatus))
+		sb.WriteString("\n")
+	}
+
 	if reason := e.Reason(); reason != nil {
 		sb.WriteString("Reason: ")
 		sb.WriteString(*reason)
@@ -408,7 +1073,7 @@ func (e *Error) formatVerbose() string {
 			printTab(&sb)
 			sb.WriteString(k)
 			sb.WriteString(": ")
-			sb.WriteString(v)
+			sb.WriteString(truncateMetadataValue(v))
 			sb.WriteString("\n")
 		}
 	}
@@ -456,19 +1121,17 @@ func (e *Error) formatVerbose() string {
 
 	if fieldViolations := e.FieldViolations(); len(fieldViolations) > 0 {
 		sb.WriteString("FieldViolations:\n")
-		for _, violation := range fieldViolations {
-			printTab(&sb)
-			sb.WriteString("FieldViolation:\n")
-			printTab(&sb)
+		for _, group := range groupFieldViolations(fieldViolations) {
 			printTab(&sb)
-			sb.WriteString("Field: ")
-			sb.WriteString(violation.Field)
-			sb.WriteString("\n")
-			printTab(&sb)
-			printTab(&sb)
-			sb.WriteString("Description: ")
-			sb.WriteString(violation.Description)
-			sb.WriteString("\n")
+			sb.WriteString(group.A)
+			sb.WriteString(":\n")
+			for _, description := range group.B {
+				printTab(&sb)
+				printTab(&sb)
+				sb.WriteString("- ")
+				sb.WriteString(description)
+				sb.WriteString("\n")
+			}
 		}
 	}
 
@@ -496,6 +1159,12 @@ func (e *Error) formatVerbose() string {
 		sb.WriteString("\n")
 	}
 
+	if spanKind := e.SpanKind(); spanKind != SpanKindUnspecified {
+		sb.WriteString("SpanKind: ")
+		sb.WriteString(spanKind.String())
+		sb.WriteString("\n")
+	}
+
 	if requestID := e.RequestID(); requestID != nil {
 		sb.WriteString("RequestId: ")
 		sb.WriteString(*requestID)
@@ -521,7 +1190,8 @@ func (e *Error) formatVerbose() string {
 		sb.WriteString("Description: ")
 		sb.WriteString(help.Description)
 		printTab(&sb)
-		sb.WriteString("	URL: ")
+		printTab(&sb)
+		sb.WriteString("URL: ")
 		sb.WriteString(help.URL)
 		sb.WriteString("\n")
 	}
@@ -560,12 +1230,58 @@ func (e *Error) formatVerbose() string {
 		}
 	}
 
+	if localizationKey := e.LocalizationKey(); localizationKey != nil {
+		sb.WriteString("LocalizationKey: ")
+		sb.WriteString(*localizationKey)
+		sb.WriteString("\n")
+	}
+
 	if retry := e.Retry(); lo.IsNotEmpty(retry) {
 		sb.WriteString("Retry:\n")
 		printTab(&sb)
 		sb.WriteString("Delay: ")
 		sb.WriteString(retry.Delay.String())
 		sb.WriteString("\n")
+		if retry.MaxAttempts != 0 {
+			printTab(&sb)
+			sb.WriteString("MaxAttempts: ")
+			sb.WriteString(strconv.Itoa(retry.MaxAttempts))
+			sb.WriteString("\n")
+		}
+		if retry.Attempt != 0 {
+			printTab(&sb)
+			sb.WriteString("Attempt: ")
+			sb.WriteString(strconv.Itoa(retry.Attempt))
+			sb.WriteString("\n")
+		}
+		if retry.Strategy != BackoffConstant {
+			printTab(&sb)
+			sb.WriteString("Strategy: ")
+			sb.WriteString(retry.Strategy.String())
+			sb.WriteString("\n")
+		}
+		if retry.Multiplier != 0 {
+			printTab(&sb)
+			sb.WriteString("Multiplier: ")
+			sb.WriteString(strconv.FormatFloat(retry.Multiplier, 'g', -1, 64))
+			sb.WriteString("\n")
+		}
+		if retry.MaxDelay != 0 {
+			printTab(&sb)
+			sb.WriteString("MaxDelay: ")
+			sb.WriteString(retry.MaxDelay.String())
+			sb.WriteString("\n")
+		}
+	}
+
+	if e.IsReported() {
+		sb.WriteString("Reported: true\n")
+	}
+
+	if goroutineID := e.GoroutineID(); goroutineID != 0 {
+		sb.WriteString("GoroutineId: ")
+		sb.WriteString(strconv.FormatUint(goroutineID, 10))
+		sb.WriteString("\n")
 	}
 
 	if st := e.StackTrace(); st != "" {
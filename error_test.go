@@ -1,12 +1,23 @@
 package errors_test
 
 import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"io/fs"
 	"log/slog"
+	"net"
+	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
 	"testing"
+	"time"
 
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/notjustmoney/errors"
@@ -91,3 +102,1310 @@ func f() error {
 		WithTag("identity").
 		Errorf("Invalid refresh token")
 }
+
+func TestRoot(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(a(), &err)
+
+	root := err.Root()
+	is.Equal("Invalid refresh token", root.Error())
+	is.NotErrorAs(root, new(*errors.Error))
+
+	is.ErrorAs(errors.New("invalid token"), &err)
+	is.Same(err, err.Root())
+}
+
+func TestChain(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(a(), &err)
+
+	chain := err.Chain()
+	is.Len(chain, 6)
+	is.Same(err, chain[0])
+	is.Nil(chain[0].Detach().Reason())
+	is.Equal("ERROR_REASON_D", *chain[3].Detach().Reason())
+	is.Equal("ERROR_REASON_INVALID_REFRESH_TOKEN", *chain[5].Detach().Reason())
+}
+
+func TestMergedMetadata(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(
+		errors.
+			WithMetadata("handler", "refresh").
+			WithMetadata("refreshToken", "redacted").
+			Wrap(f()),
+		&err,
+	)
+
+	is.Equal(map[string]string{"refreshToken": "refresh-token-string"}, err.Metadata())
+	is.Equal(map[string]string{
+		"handler":      "refresh",
+		"refreshToken": "redacted",
+	}, err.MergedMetadata())
+
+	is.ErrorAs(errors.New("invalid token"), &err)
+	is.Nil(err.MergedMetadata())
+}
+
+func TestGetMetadata(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(
+		errors.
+			WithMetadata("handler", "refresh").
+			WithMetadata("refreshToken", "redacted").
+			Wrap(f()),
+		&err,
+	)
+
+	value, ok := err.GetMetadata("handler")
+	is.True(ok)
+	is.Equal("refresh", value)
+
+	value, ok = err.GetMetadata("refreshToken")
+	is.True(ok)
+	is.Equal("redacted", value)
+
+	value, ok = err.GetMetadata("client")
+	is.False(ok)
+	is.Equal("", value)
+}
+
+func TestWithMetadataMap(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(
+		errors.
+			WithMetadata("handler", "refresh").
+			WithMetadata("client", "web").
+			WithMetadataMap(map[string]string{"client": "ios", "version": "1.2.3"}).
+			Error("invalid refresh token"),
+		&err,
+	)
+	is.Equal(map[string]string{
+		"handler": "refresh",
+		"client":  "ios",
+		"version": "1.2.3",
+	}, err.Metadata())
+
+	is.ErrorAs(errors.WithMetadataMap(map[string]string{"client": "web"}).Error("invalid refresh token"), &err)
+	is.Equal(map[string]string{"client": "web"}, err.Metadata())
+}
+
+func TestAllFieldViolations(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(
+		errors.
+			WithFieldViolation("clientId", "required").
+			WithQuotaViolation("user/42", "too many refreshes").
+			Wrap(a()),
+		&err,
+	)
+
+	is.Equal([]errors.FieldViolation{{Field: "refreshToken", Description: "refresh-token-string"}}, err.FieldViolations())
+	is.Equal([]errors.FieldViolation{
+		{Field: "clientId", Description: "required"},
+		{Field: "refreshToken", Description: "refresh-token-string"},
+	}, err.AllFieldViolations())
+	is.Equal([]errors.QuotaViolation{{Subject: "user/42", Description: "too many refreshes"}}, err.AllQuotaViolations())
+	is.Empty(err.AllPreconditionViolations())
+}
+
+func TestTransportView(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(a(), &err)
+
+	view := err.TransportView()
+	is.Equal(errors.TransportVersion, view.Version)
+	is.Equal("ERROR_REASON_INVALID_REFRESH_TOKEN", view.Reason)
+	is.Equal("identity", view.Domain)
+	is.Equal("Invalid refresh token", view.Message)
+	is.Equal([]errors.FieldViolation{{Field: "refreshToken", Description: "refresh-token-string"}}, view.FieldViolations)
+}
+
+func TestFieldViolationGrouping(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(
+		errors.
+			WithFieldViolation("password", "must be at least 8 characters").
+			WithFieldViolation("email", "must not be empty").
+			WithFieldViolation("password", "must contain a digit").
+			Error("invalid request"),
+		&err,
+	)
+
+	is.Equal(map[string][]string{
+		"password": {"must be at least 8 characters", "must contain a digit"},
+		"email":    {"must not be empty"},
+	}, err.FieldViolationMap())
+	is.Contains(
+		fmt.Sprintf("%+v", err),
+		"FieldViolations:\n\tpassword:\n\t\t- must be at least 8 characters\n\t\t- must contain a digit\n\temail:\n\t\t- must not be empty\n",
+	)
+}
+
+func TestNewf(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(errors.Newf("user %d not found", 42), &err)
+	is.Equal("user 42 not found", err.Error())
+	is.Equal("user 42 not found", *err.Message())
+	is.Nil(err.Unwrap())
+}
+
+func TestMessage(t *testing.T) {
+	is := assert.New(t)
+
+	for _, tc := range []struct {
+		name    string
+		err     error
+		message string
+		text    string
+	}{
+		{"New", errors.New("not found"), "not found", "not found"},
+		{"Error", errors.Reason("NOT_FOUND").Error("not found"), "not found", "not found"},
+		{"Errorf", errors.Errorf("user %d: %w", 42, fs.ErrNotExist), "user 42: file does not exist", "user 42: file does not exist"},
+		{"Wrapf", errors.Wrapf(fs.ErrNotExist, "user %d", 42), "user 42", "user 42: file does not exist"},
+		{"Wrapf same text", errors.Wrapf(stderrors.New("timeout"), "timeout"), "timeout", "timeout: timeout"},
+		{"Wrap Errorf", errors.ToBuilder(errors.Errorf("timeout").(*errors.Error)).Wrap(fs.ErrNotExist), "timeout", "timeout: file does not exist"},
+	} {
+		var err *errors.Error
+		is.ErrorAs(tc.err, &err, tc.name)
+		if is.NotNil(err.Message(), tc.name) {
+			is.Equal(tc.message, *err.Message(), tc.name)
+		}
+		is.Equal(tc.text, err.Error(), tc.name)
+	}
+}
+
+type legacyError struct {
+	cause error
+}
+
+func (e *legacyError) Error() string { return "legacy: " + e.cause.Error() }
+func (e *legacyError) Cause() error  { return e.cause }
+
+func TestWithCauseChain(t *testing.T) {
+	is := assert.New(t)
+
+	chain := &legacyError{cause: errors.Reason("ERROR_REASON_NOT_FOUND").Wrap(fs.ErrNotExist)}
+
+	var err *errors.Error
+	is.ErrorAs(errors.Domain("identity").WithCauseChain(chain), &err)
+	is.True(errors.Is(err, fs.ErrNotExist))
+	is.Equal("ERROR_REASON_NOT_FOUND", *err.Reason())
+	is.Equal("legacy: file does not exist", err.Error())
+
+	is.Nil(errors.WithCauseChain(nil))
+}
+
+func TestSpanKind(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(errors.Wrap(errors.Trace("trace").SpanKind(errors.SpanKindClient).Error("upstream unavailable")), &err)
+	is.Equal(errors.SpanKindClient, err.SpanKind())
+	is.Contains(fmt.Sprintf("%+v", err), "SpanKind: client\n")
+
+	is.ErrorAs(errors.New("not found"), &err)
+	is.Equal(errors.SpanKindUnspecified, err.SpanKind())
+	is.NotContains(fmt.Sprintf("%+v", err), "SpanKind:")
+}
+
+func TestReported(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(errors.Wrap(errors.Reported().Wrap(fs.ErrNotExist)), &err)
+	is.True(err.IsReported())
+
+	is.ErrorAs(errors.Wrap(fs.ErrNotExist), &err)
+	is.False(err.IsReported())
+}
+
+func TestOrigin(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(a(), &err)
+
+	file, line, function := err.Origin()
+	is.True(strings.HasSuffix(file, "error_test.go"))
+	is.Positive(line)
+	is.Equal("f", function)
+}
+
+func TestSource(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(
+		errors.Wrap(errors.
+			Source("/srv/gen/rules.go", 12, "MinLength").
+			Error("too short")),
+		&err,
+	)
+
+	file, line, function := err.Origin()
+	is.Equal("/srv/gen/rules.go", file)
+	is.Equal(12, line)
+	is.Equal("MinLength", function)
+	is.Contains(err.StackTrace(), "  --- at /srv/gen/rules.go:12 MinLength()")
+}
+
+func TestWithStackTraceString(t *testing.T) {
+	is := assert.New(t)
+
+	imported := "  --- at /srv/billing/invoice.go:42 CreateInvoice()"
+
+	var err *errors.Error
+	is.ErrorAs(
+		errors.Wrap(errors.
+			Reason("ERROR_REASON_REMOTE").
+			WithStackTraceString(imported).
+			Error("invoice creation failed")),
+		&err,
+	)
+
+	st := err.StackTrace()
+	is.True(strings.HasPrefix(st, "Error: invoice creation failed\n"+imported+"\nThrown: invoice creation failed\n"))
+	is.Contains(fmt.Sprintf("%+v", err), imported)
+}
+
+func TestTagSortMode(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(errors.WithTag("zeta").Wrap(errors.WithTag("alpha").WithTag("zeta").Error("failed")), &err)
+	is.Equal([]string{"zeta", "alpha"}, err.Tags())
+
+	errors.SetTagSortMode(errors.TagSortAlphabetical)
+	defer errors.SetTagSortMode(errors.TagSortInsertion)
+	is.Equal([]string{"alpha", "zeta"}, err.Tags())
+}
+
+func TestHas(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(
+		errors.Reason("ERROR_REASON_REFRESH_FAILED").Domain("gateway").WithTag("http").
+			Wrap(errors.Join(f(), errors.Reason("ERROR_REASON_TIMEOUT").Error("timeout"))),
+		&err,
+	)
+
+	is.True(err.HasTag("http"))
+	is.True(err.HasTag("identity"))
+	is.False(err.HasTag("billing"))
+
+	is.True(err.HasReason("ERROR_REASON_REFRESH_FAILED"))
+	is.True(err.HasReason("ERROR_REASON_INVALID_REFRESH_TOKEN"))
+	is.True(err.HasReason("ERROR_REASON_TIMEOUT"))
+	is.False(err.HasReason("ERROR_REASON_NOT_FOUND"))
+
+	is.True(err.HasDomain("gateway"))
+	is.True(err.HasDomain("identity"))
+	is.False(err.HasDomain("billing"))
+
+	errors.SetInferDomainFromReason(true)
+	defer errors.SetInferDomainFromReason(false)
+	is.True(errors.Reason("billing.CARD_DECLINED").Build().HasDomain("billing"))
+}
+
+func TestWithTags(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(errors.WithTags("auth", "token").Wrap(errors.WithTag("db").WithTags("token", "timeout").Error("failed")), &err)
+	is.Equal([]string{"auth", "token", "db", "timeout"}, err.Tags())
+
+	is.ErrorAs(errors.WithTags().Error("failed"), &err)
+	is.Empty(err.Tags())
+}
+
+func TestIf(t *testing.T) {
+	is := assert.New(t)
+
+	build := func(userID string) *errors.Error {
+		return errors.
+			Reason("ERROR_REASON_INVALID_TOKEN").
+			If(userID != "", func(b errors.ErrorBuilder) errors.ErrorBuilder {
+				return b.UserID(userID).WithTag("user")
+			}).
+			Build()
+	}
+
+	err := build("user-42")
+	is.Equal("user-42", *err.UserID())
+	is.Equal([]string{"user"}, err.Tags())
+	is.Equal("ERROR_REASON_INVALID_TOKEN", *err.Reason())
+
+	err = build("")
+	is.Nil(err.UserID())
+	is.Empty(err.Tags())
+	is.Equal("ERROR_REASON_INVALID_TOKEN", *err.Reason())
+}
+
+func TestClone(t *testing.T) {
+	is := assert.New(t)
+
+	base := errors.
+		Reason("ERROR_REASON_INVALID_TOKEN").
+		WithMetadata("client", "web").
+		WithFieldViolation("refreshToken", "expired").
+		RequestID("request-1").
+		Build()
+
+	clone := base.Clone()
+	is.NotSame(base, clone)
+	is.Equal(base.Error(), clone.Error())
+	is.Equal(base.RequestID(), clone.RequestID())
+	is.Equal(base.Time(), clone.Time())
+	is.Equal(base.StackTrace(), clone.StackTrace())
+
+	clone.Metadata()["client"] = "ios"
+	clone.FieldViolations()[0].Description = "revoked"
+	is.Equal(map[string]string{"client": "web"}, base.Metadata())
+	is.Equal("expired", base.FieldViolations()[0].Description)
+}
+
+func TestToBuilder(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(errors.Reason("ERROR_REASON_INVALID_TOKEN").WithMetadata("client", "web").Wrap(fs.ErrNotExist), &err)
+
+	enriched := errors.ToBuilder(err).WithMetadata("version", "1.2.3").WithTag("auth").Build()
+	is.Equal(err.Error(), enriched.Error())
+	is.Equal("ERROR_REASON_INVALID_TOKEN", *enriched.Reason())
+	is.Equal(map[string]string{"client": "web", "version": "1.2.3"}, enriched.Metadata())
+	is.Equal([]string{"auth"}, enriched.Tags())
+	is.ErrorIs(enriched, fs.ErrNotExist)
+	is.Len(enriched.Chain(), 1)
+	is.Equal(map[string]string{"client": "web"}, err.Metadata())
+	is.Empty(err.Tags())
+
+	is.Equal("", errors.ToBuilder(nil).Build().Error())
+}
+
+func TestWithoutStack(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(errors.Wrap(errors.Join(f(), errors.Wrap(fs.ErrNotExist))), &err)
+	is.NotEmpty(err.StackTrace())
+
+	stripped := err.WithoutStack()
+	is.Empty(stripped.StackTrace())
+	is.Empty(stripped.Sources())
+	is.Empty(stripped.Frames())
+	for _, layer := range stripped.Chain() {
+		is.Empty(layer.StackTrace())
+	}
+	is.Equal(err.Error(), stripped.Error())
+	is.Equal(err.Reason(), stripped.Reason())
+	is.ErrorIs(stripped, fs.ErrNotExist)
+
+	data, jsonErr := json.Marshal(stripped)
+	is.NoError(jsonErr)
+	is.NotContains(string(data), "stackTrace")
+
+	is.NotEmpty(err.StackTrace())
+
+	t.Run("foreign wrapper", func(t *testing.T) {
+		is := assert.New(t)
+
+		var err *errors.Error
+		is.ErrorAs(errors.Wrap(fmt.Errorf("context: %w", errors.Wrap(fs.ErrNotExist))), &err)
+		is.NotEmpty(err.StackTrace())
+
+		stripped := err.WithoutStack()
+		is.Empty(stripped.StackTrace())
+		for _, layer := range stripped.Chain() {
+			is.Empty(layer.StackTrace())
+		}
+		is.Equal(err.Error(), stripped.Error())
+		is.ErrorIs(stripped, fs.ErrNotExist)
+		is.NotEmpty(err.StackTrace())
+	})
+
+	t.Run("cause chain", func(t *testing.T) {
+		is := assert.New(t)
+
+		var err *errors.Error
+		is.ErrorAs(errors.Wrap(errors.WithCauseChain(&legacyError{cause: errors.New("inner")})), &err)
+		is.NotEmpty(err.StackTrace())
+
+		stripped := err.WithoutStack()
+		is.Empty(stripped.StackTrace())
+		is.Equal(err.Error(), stripped.Error())
+	})
+
+	t.Run("sentinel", func(t *testing.T) {
+		is := assert.New(t)
+
+		errNotFound := errors.Reason("ERROR_REASON_NOT_FOUND").Error("not found")
+
+		var err *errors.Error
+		is.ErrorAs(errors.Wrap(errNotFound), &err)
+
+		stripped := err.WithoutStack()
+		is.Empty(stripped.StackTrace())
+		is.ErrorIs(stripped, errNotFound)
+		is.ErrorIs(stripped, err)
+
+		is.ErrorAs(errors.Wrap(fmt.Errorf("lookup: %w", err)), &err)
+		is.ErrorIs(err.WithoutStack(), errNotFound)
+	})
+
+	t.Run("IDs", func(t *testing.T) {
+		is := assert.New(t)
+
+		var err *errors.Error
+		is.ErrorAs(errors.Wrap(errors.Wrap(fs.ErrNotExist)), &err)
+		is.Equal(*err.Trace(), *err.WithoutStack().Trace())
+		is.Equal(*err.Span(), *err.WithoutStack().Span())
+
+		is.ErrorAs(errors.Wrap(errors.Wrap(fs.ErrNotExist)), &err)
+		stripped := err.WithoutStack()
+		is.Equal(*stripped.Trace(), *err.Trace())
+		is.Equal(*stripped.Span(), *err.Span())
+	})
+}
+
+func TestBuild(t *testing.T) {
+	is := assert.New(t)
+
+	err := errors.Reason("ERROR_REASON_NOT_FOUND").Domain("identity").Build()
+	is.Equal("ERROR_REASON_NOT_FOUND", *err.Reason())
+	is.Equal("identity", *err.Domain())
+	is.Equal("", err.Error())
+	is.Nil(err.Unwrap())
+
+	_, _, function := err.Origin()
+	is.Equal("TestBuild", function)
+}
+
+func TestWrapOnce(t *testing.T) {
+	is := assert.New(t)
+
+	err := errors.Wrap(fs.ErrNotExist)
+	is.Same(err, errors.WrapOnce(err))
+	is.NotSame(err, errors.Wrap(err))
+
+	wrapped := errors.WrapOnce(fs.ErrNotExist)
+	var e *errors.Error
+	is.ErrorAs(wrapped, &e)
+	is.NotEmpty(e.StackTrace())
+	is.True(errors.Is(wrapped, fs.ErrNotExist))
+
+	is.Nil(errors.WrapOnce(nil))
+}
+
+func TestDetach(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(errors.Reason("ERROR_REASON_STORAGE").Wrapf(fs.ErrPermission, "read secret"), &err)
+
+	detached := err.Detach()
+	is.Nil(detached.Unwrap())
+	is.False(errors.Is(detached, fs.ErrPermission))
+	is.Equal("read secret", detached.Error())
+	is.Equal("ERROR_REASON_STORAGE", *detached.Reason())
+	is.Equal(err.StackTrace(), detached.StackTrace())
+
+	is.True(errors.Is(err, fs.ErrPermission))
+}
+
+func TestFrames(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(a(), &err)
+
+	frames := err.Frames()
+	is.Equal(
+		[]string{"f", "e", "d", "c", "b", "a", "TestFrames"},
+		lo.Map(frames, func(frame errors.Frame, _ int) string { return frame.Function }),
+	)
+	for _, frame := range frames {
+		is.True(strings.HasSuffix(frame.File, "error_test.go"))
+		is.Positive(frame.Line)
+		is.NotZero(frame.PC)
+	}
+
+	err = errors.Reason("ERROR_REASON_REMOTE").WithStackTraceString("  --- at remote.go:1 remote()").Build()
+	is.Equal(
+		[]string{"TestFrames"},
+		lo.Map(err.Frames(), func(frame errors.Frame, _ int) string { return frame.Function }),
+	)
+}
+
+func TestInferDomainFromReason(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(errors.Reason("identity.INVALID_TOKEN").Error("invalid token"), &err)
+	is.Nil(err.Domain())
+
+	errors.SetInferDomainFromReason(true)
+	defer errors.SetInferDomainFromReason(false)
+
+	is.Equal("identity", *err.Domain())
+
+	is.ErrorAs(errors.Reason("identity.INVALID_TOKEN").Domain("auth").Error("invalid token"), &err)
+	is.Equal("auth", *err.Domain())
+
+	is.ErrorAs(errors.Reason("INVALID_TOKEN").Error("invalid token"), &err)
+	is.Nil(err.Domain())
+}
+
+func TestVerboseShowCauseType(t *testing.T) {
+	is := assert.New(t)
+
+	err := errors.Wrap(errors.Wrap(&fs.PathError{Op: "open", Path: "/etc/app.yaml", Err: syscall.ENOENT}))
+	is.NotContains(fmt.Sprintf("%+v", err), "CauseType:")
+
+	errors.SetVerboseShowCauseType(true)
+	defer errors.SetVerboseShowCauseType(false)
+
+	is.Contains(fmt.Sprintf("%+v", err), "CauseType: syscall.Errno\n")
+	is.NotContains(fmt.Sprintf("%+v", errors.New("invalid token")), "CauseType:")
+
+	err = errors.Errorf("connect: %w", &net.OpError{Op: "dial", Net: "tcp", Err: &net.AddrError{Err: "missing port in address", Addr: "localhost"}})
+	is.Contains(fmt.Sprintf("%+v", err), "CauseType: *net.AddrError\n")
+
+	err = errors.Wrap(errors.WithCauseChain(&legacyError{cause: &net.AddrError{Err: "missing port in address"}}))
+	is.Contains(fmt.Sprintf("%+v", err), "CauseType: *net.AddrError\n")
+}
+
+func TestVerboseIndent(t *testing.T) {
+	is := assert.New(t)
+
+	err := errors.WithMetadata("refreshToken", "token").Error("invalid refresh token")
+	is.Contains(fmt.Sprintf("%+v", err), "Metadata:\n\trefreshToken: token\n")
+
+	errors.SetVerboseIndent("  ")
+	defer errors.SetVerboseIndent("\t")
+
+	is.Contains(fmt.Sprintf("%+v", err), "Metadata:\n  refreshToken: token\n")
+}
+
+func TestCaptureGoroutineID(t *testing.T) {
+	is := assert.New(t)
+
+	is.NotContains(fmt.Sprintf("%+v", errors.New("failed")), "GoroutineId:")
+
+	errors.SetCaptureGoroutineID(true)
+	defer errors.SetCaptureGoroutineID(false)
+
+	is.Regexp(`GoroutineId: [1-9][0-9]*\n`, fmt.Sprintf("%+v", errors.New("failed")))
+
+	done := make(chan error)
+	go func() {
+		done <- errors.New("failed")
+	}()
+
+	var err *errors.Error
+	is.ErrorAs(errors.Wrap(<-done), &err)
+	is.NotZero(err.GoroutineID())
+	is.NotEqual(errors.Reason("FAILED").Build().GoroutineID(), err.GoroutineID())
+}
+
+func TestSummary(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(a(), &err)
+	is.Regexp(`^ERROR_REASON_INVALID_REFRESH_TOKEN \(identity\): Invalid refresh token @ .*error_test\.go:\d+$`, err.Summary())
+
+	is.Regexp(`^not found @ .*error_test\.go:\d+$`, errors.Newf("not found").(*errors.Error).Summary())
+
+	is.Empty((&errors.Error{}).Summary())
+}
+
+func TestHTTPStatus(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(errors.Wrap(errors.HTTPStatus(http.StatusNotFound).Error("user not found")), &err)
+	is.Equal(http.StatusNotFound, err.HTTPStatus())
+	is.Contains(fmt.Sprintf("%+v", err), "HTTPStatus: 404\n")
+
+	is.ErrorAs(errors.New("failed"), &err)
+	is.Equal(http.StatusInternalServerError, err.HTTPStatus())
+	is.NotContains(fmt.Sprintf("%+v", err), "HTTPStatus:")
+}
+
+func TestJoinBranches(t *testing.T) {
+	is := assert.New(t)
+
+	err := errors.WithTag("outer").Join(
+		errors.WithTag("first").WithFieldViolation("email", "required").Wrap(fs.ErrExist),
+		nil,
+		errors.WithTag("second").WithFieldViolation("name", "required").Wrap(fs.ErrNotExist),
+	)
+
+	var ee *errors.Error
+	is.ErrorAs(err, &ee)
+	is.ElementsMatch([]string{"outer", "first", "second"}, ee.Tags())
+	is.Len(ee.FieldViolations(), 2)
+	is.True(errors.Is(err, fs.ErrExist))
+	is.True(errors.Is(err, fs.ErrNotExist))
+
+	is.Nil(errors.Join(nil, nil))
+}
+
+func TestStackTraceEnabled(t *testing.T) {
+	is := assert.New(t)
+
+	errors.SetStackTraceEnabled(false)
+	defer errors.SetStackTraceEnabled(true)
+
+	var err *errors.Error
+	is.ErrorAs(errors.Wrap(errors.New("invalid token")), &err)
+	is.Empty(err.StackTrace())
+	is.Empty(err.Sources())
+	is.Empty(err.Frames())
+
+	file, line, function := err.Origin()
+	is.Empty(file)
+	is.Zero(line)
+	is.Empty(function)
+}
+
+func BenchmarkWrap(b *testing.B) {
+	cause := errors.New("invalid token")
+
+	b.Run("stack", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = errors.Wrap(cause)
+		}
+	})
+	b.Run("no stack", func(b *testing.B) {
+		errors.SetStackTraceEnabled(false)
+		defer errors.SetStackTraceEnabled(true)
+
+		for i := 0; i < b.N; i++ {
+			_ = errors.Wrap(cause)
+		}
+	})
+	b.Run("foreign no stack", func(b *testing.B) {
+		errors.SetStackTraceEnabled(false)
+		defer errors.SetStackTraceEnabled(true)
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = errors.Wrap(fs.ErrNotExist)
+		}
+	})
+}
+
+func BenchmarkBuilderPool(b *testing.B) {
+	errors.SetStackTraceEnabled(false)
+	defer errors.SetStackTraceEnabled(true)
+
+	b.Run("value", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = errors.
+				WithMetadata("user", "42").
+				WithFieldViolation("refreshToken", "expired").
+				WithTag("auth").
+				Wrap(fs.ErrNotExist)
+		}
+	})
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			builder := errors.AcquireBuilder()
+			_ = builder.
+				WithMetadata("user", "42").
+				WithFieldViolation("refreshToken", "expired").
+				WithTag("auth").
+				Wrap(fs.ErrNotExist)
+			errors.ReleaseBuilder(builder)
+		}
+	})
+}
+
+func BenchmarkStackTrace(b *testing.B) {
+	var err *errors.Error
+	if !stderrors.As(errors.Wrap(errors.Wrap(errors.Wrap(errors.New("invalid token")))), &err) {
+		b.Fatal("not an *errors.Error")
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = err.StackTrace()
+	}
+}
+
+func TestStackDepth(t *testing.T) {
+	is := assert.New(t)
+
+	build := func(b errors.ErrorBuilder) *errors.Error {
+		return b.Build()
+	}
+
+	is.Len(build(errors.Reason("INVALID_TOKEN")).Frames(), 2)
+	is.Len(build(errors.StackDepth(1)).Frames(), 1)
+
+	err := build(errors.StackDepth(0))
+	is.Empty(err.Frames())
+	is.Empty(err.StackTrace())
+}
+
+func invalidTokenHelper(description string) error {
+	return errors.SkipCallers(1).Reason("INVALID_TOKEN").Error("invalid token: " + description)
+}
+
+func TestSkipCallers(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(invalidTokenHelper("expired"), &err)
+
+	_, _, function := err.Origin()
+	is.Equal("TestSkipCallers", function)
+	is.NotContains(err.StackTrace(), "invalidTokenHelper")
+}
+
+func TestRuntimeFrames(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(errors.Wrap(a()), &err)
+
+	frames := err.RuntimeFrames()
+	is.NotEmpty(frames)
+	is.True(strings.HasSuffix(frames[0].Function, ".f"))
+	is.True(strings.HasSuffix(frames[0].File, "error_test.go"))
+
+	_, line, _ := err.Origin()
+	is.Equal(line, frames[0].Line)
+}
+
+func TestSourceContextLines(t *testing.T) {
+	is := assert.New(t)
+
+	err := errors.New("invalid token")
+	is.Len(strings.Split(err.(*errors.Error).Sources(), "\n"), 2+11+1)
+
+	errors.SetSourceContextLines(1, 0)
+	defer errors.SetSourceContextLines(5, 5)
+
+	sources := strings.Split(err.(*errors.Error).Sources(), "\n")
+	is.Len(sources, 2+2+1)
+	is.Contains(sources[len(sources)-2], `err := errors.New("invalid token")`)
+
+	errors.SetSourceContextLines(-1, 1000)
+	sources = strings.Split(err.(*errors.Error).Sources(), "\n")
+	is.Contains(sources[2], `err := errors.New("invalid token")`)
+}
+
+func TestShouldRetry(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(errors.Wrap(errors.RetryPolicy(time.Second, 3).Error("unavailable")), &err)
+	is.Equal(errors.Retry{Delay: time.Second, MaxAttempts: 3}, err.Retry())
+	is.True(err.ShouldRetry())
+	is.Contains(fmt.Sprintf("%+v", err), "Retry:\n\tDelay: 1s\n\tMaxAttempts: 3\n")
+
+	is.ErrorAs(errors.Reason("UNAVAILABLE").Retry(errors.Retry{Delay: time.Second, MaxAttempts: 3, Attempt: 3}).Error("unavailable"), &err)
+	is.False(err.ShouldRetry())
+
+	is.ErrorAs(errors.New("unavailable"), &err)
+	is.False(err.ShouldRetry())
+}
+
+func TestBackoff(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(errors.RetryPolicy(100*time.Millisecond, 10).Backoff(errors.BackoffExponential, 2, time.Second).Error("unavailable"), &err)
+
+	retry := err.Retry()
+	is.Equal(100*time.Millisecond, retry.DelayForAttempt(1))
+	is.Equal(200*time.Millisecond, retry.DelayForAttempt(2))
+	is.Equal(400*time.Millisecond, retry.DelayForAttempt(3))
+	is.Equal(800*time.Millisecond, retry.DelayForAttempt(4))
+	is.Equal(time.Second, retry.DelayForAttempt(5))
+	is.Equal(time.Second, retry.DelayForAttempt(100))
+
+	linear := errors.Retry{Delay: time.Second, Strategy: errors.BackoffLinear}
+	is.Equal(3*time.Second, linear.DelayForAttempt(3))
+
+	constant := errors.Retry{Delay: time.Second}
+	is.Equal(time.Second, constant.DelayForAttempt(3))
+}
+
+func TestTraceConcurrent(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(errors.Wrap(errors.Wrap(errors.New("invalid token"))), &err)
+
+	traces := make(chan string, 16)
+	var wg sync.WaitGroup
+	for i := 0; i < cap(traces); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			traces <- *err.Trace()
+			_, _ = err.MarshalJSON()
+		}()
+	}
+	wg.Wait()
+	close(traces)
+
+	want := *err.Trace()
+	for trace := range traces {
+		is.Equal(want, trace)
+	}
+}
+
+func TestTraceSentinel(t *testing.T) {
+	is := assert.New(t)
+
+	errNotFound := errors.Reason("NOT_FOUND").Error("not found")
+
+	var first, second *errors.Error
+	is.ErrorAs(errors.Wrap(errNotFound), &first)
+	is.ErrorAs(errors.Wrap(errNotFound), &second)
+
+	is.NotEqual(*first.Trace(), *second.Trace())
+	is.Equal(*first.Trace(), *first.Trace())
+
+	var sentinel *errors.Error
+	is.ErrorAs(errNotFound, &sentinel)
+	is.NotEqual(*first.Trace(), *sentinel.Trace())
+	is.NotEqual(*second.Trace(), *sentinel.Trace())
+
+	is.ErrorAs(errors.Wrap(errors.Trace("trace-1").Wrap(errNotFound)), &first)
+	is.Equal("trace-1", *first.Trace())
+}
+
+func TestSpanConcurrent(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(errors.Wrap(errors.Wrap(fs.ErrNotExist)), &err)
+
+	spans := make(chan string, 16)
+	var wg sync.WaitGroup
+	for i := 0; i < cap(spans); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			spans <- *err.Span()
+			_, _ = err.MarshalJSON()
+			_ = err.StackTrace()
+		}()
+	}
+	wg.Wait()
+	close(spans)
+
+	want := *err.Span()
+	for span := range spans {
+		is.Equal(want, span)
+	}
+}
+
+func TestBuilderPoolConcurrent(t *testing.T) {
+	is := assert.New(t)
+
+	errs := make(chan *errors.Error, 64)
+	var wg sync.WaitGroup
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b := errors.AcquireBuilder()
+			defer errors.ReleaseBuilder(b)
+
+			id := strconv.Itoa(i)
+			var err *errors.Error
+			is.ErrorAs(b.WithMetadata("id", id).WithFieldViolation("id", id).WithTag(id).Wrap(fs.ErrNotExist), &err)
+			is.Equal(*err.Span(), *err.Span())
+			is.Equal(*err.Trace(), *err.Trace())
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		id := err.Metadata()["id"]
+		is.Equal(map[string]string{"id": id}, err.Metadata())
+		is.Equal([]errors.FieldViolation{{Field: "id", Description: id}}, err.FieldViolations())
+		is.Equal([]string{id}, err.Tags())
+		is.Nil(err.Attributes())
+		is.False(err.Time().IsZero())
+	}
+}
+
+func TestSpanMarshalJSON(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(errors.Wrap(fs.ErrNotExist), &err)
+
+	data, marshalErr := err.MarshalJSON()
+	is.NoError(marshalErr)
+	is.NotContains(string(data), `"span"`)
+
+	span := *err.Span()
+	data, marshalErr = err.MarshalJSON()
+	is.NoError(marshalErr)
+	is.Contains(string(data), `"span":"`+span+`"`)
+}
+
+func TestTime(t *testing.T) {
+	is := assert.New(t)
+
+	before := time.Now()
+	builder := errors.Reason("ERROR_REASON_INVALID_TOKEN")
+	after := time.Now()
+
+	var err *errors.Error
+	is.ErrorAs(errors.Wrap(builder.Errorf("invalid token: %w", fs.ErrInvalid)), &err)
+
+	want := err.Time()
+	is.False(want.Before(before))
+	is.False(want.After(after))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			is.Equal(want, err.Time())
+		}()
+	}
+	wg.Wait()
+}
+
+func TestRequestID(t *testing.T) {
+	is := assert.New(t)
+
+	builder := errors.Reason("ERROR_REASON_INVALID_TOKEN").RequestID("req-1")
+
+	var err *errors.Error
+	is.ErrorAs(builder.Errorf("invalid token: %w", fs.ErrInvalid), &err)
+	is.Equal("req-1", *err.RequestID())
+
+	is.ErrorAs(builder.Error("invalid token"), &err)
+	is.Equal("req-1", *err.RequestID())
+
+	is.ErrorAs(builder.Wrapf(fs.ErrInvalid, "invalid token"), &err)
+	is.Equal("req-1", *err.RequestID())
+}
+
+func TestSpan(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(errors.Wrap(errors.Span("span-1").Error("invalid token")), &err)
+	is.Equal("span-1", *err.Span())
+
+	is.ErrorAs(errors.Span("span-2").Wrap(errors.Span("span-1").Error("invalid token")), &err)
+	is.Equal("span-1", *err.Span())
+
+	is.ErrorAs(errors.Wrap(errors.Wrap(fs.ErrNotExist)), &err)
+	span := err.Span()
+	is.NotNil(span)
+	is.Equal(span, err.Span())
+
+	is.ErrorAs(errors.Wrap(errors.Span("span-3").Wrap(fs.ErrNotExist)), &err)
+	is.Equal("span-3", *err.Span())
+
+	is.ErrorAs(errors.Span("span-4").Wrap(errors.Wrap(fs.ErrNotExist)), &err)
+	is.Equal("span-4", *err.Span())
+
+	is.ErrorAs(errors.Wrap(errors.New("invalid token")), &err)
+	is.Nil(err.Span())
+
+	errNotExist := errors.Wrap(fs.ErrNotExist)
+	var first, second *errors.Error
+	is.ErrorAs(errors.Wrap(errNotExist), &first)
+	is.ErrorAs(errors.Wrap(errNotExist), &second)
+	is.NotEqual(*first.Span(), *second.Span())
+	is.Equal(*first.Span(), *first.Span())
+	is.NotEqual(*first.Span(), *errNotExist.(*errors.Error).Span())
+}
+
+func TestLogValueMessage(t *testing.T) {
+	is := assert.New(t)
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Error("request failed", slog.Any("err", errors.Wrap(errors.Wrap(errors.New("invalid token")))))
+
+	var record struct {
+		Err struct {
+			Message string `json:"message"`
+		} `json:"err"`
+	}
+	is.NoError(json.Unmarshal(buf.Bytes(), &record))
+	is.Equal("invalid token", record.Err.Message)
+}
+
+func TestStdlibJoinBranches(t *testing.T) {
+	is := assert.New(t)
+
+	first := errors.Reason("ERROR_REASON_FIRST").WithTag("first").WithFieldViolation("email", "required").Error("first")
+	second := errors.Reason("ERROR_REASON_SECOND").WithTag("second").WithFieldViolation("name", "required").Error("second")
+
+	for _, cause := range []error{
+		stderrors.Join(first, second),
+		fmt.Errorf("%w; %w", first, second),
+	} {
+		var err *errors.Error
+		is.ErrorAs(errors.Wrap(cause), &err)
+
+		is.Equal(map[string]int{"ERROR_REASON_FIRST": 1, "ERROR_REASON_SECOND": 1}, err.ReasonCounts())
+		is.Equal("ERROR_REASON_FIRST", *err.Reason())
+		is.ElementsMatch([]string{"first", "second"}, err.Tags())
+		is.Len(err.FieldViolations(), 2)
+		is.Len(err.Chain(), 3)
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(errors.New("invalid token"), &err)
+	is.Equal(errors.SeverityError, err.Severity())
+
+	is.ErrorAs(errors.Wrap(errors.Reason("ERROR_REASON_DISK_FULL").Severity(errors.SeverityFatal).Error("disk full")), &err)
+	is.Equal(errors.SeverityFatal, err.Severity())
+	is.Equal(slog.LevelError+4, err.Severity().Level())
+	is.Equal(slog.LevelWarn, errors.SeverityWarning.Level())
+	is.Contains(fmt.Sprintf("%+v", err), "Severity: fatal\n")
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Error("request failed", slog.Any("err", err))
+
+	var record struct {
+		Err struct {
+			Severity string `json:"severity"`
+		} `json:"err"`
+	}
+	is.NoError(json.Unmarshal(buf.Bytes(), &record))
+	is.Equal("ERROR+4", record.Err.Severity)
+
+	data, jsonErr := json.Marshal(err)
+	is.NoError(jsonErr)
+	is.Contains(string(data), `"severity":"fatal"`)
+
+	got := &errors.Error{}
+	is.NoError(json.Unmarshal(data, got))
+	is.Equal(errors.SeverityFatal, got.Severity())
+}
+
+func TestCode(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(errors.New("invalid token"), &err)
+	is.Equal(errors.CodeUnknown, err.Code())
+	is.NotContains(fmt.Sprintf("%+v", err), "Code:")
+
+	is.ErrorAs(errors.Wrap(errors.Reason("ERROR_REASON_USER_NOT_FOUND").Code(errors.CodeNotFound).Error("user not found")), &err)
+	is.Equal(errors.CodeNotFound, err.Code())
+	is.Contains(fmt.Sprintf("%+v", err), "Code: NOT_FOUND\n")
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Error("request failed", slog.Any("err", err))
+
+	var record struct {
+		Err struct {
+			Code string `json:"code"`
+		} `json:"err"`
+	}
+	is.NoError(json.Unmarshal(buf.Bytes(), &record))
+	is.Equal("NOT_FOUND", record.Err.Code)
+}
+
+func TestIDGenerator(t *testing.T) {
+	is := assert.New(t)
+
+	var n int
+	errors.SetIDGenerator(func() string {
+		n++
+		return fmt.Sprintf("id-%d", n)
+	})
+	defer errors.SetIDGenerator(nil)
+
+	var err *errors.Error
+	is.ErrorAs(errors.Wrap(errors.Wrap(fs.ErrNotExist)), &err)
+	is.Equal("id-1", *err.Trace())
+	is.Equal("id-2", *err.Span())
+	is.Equal("id-1", *err.Trace())
+	is.Equal("id-2", *err.Span())
+}
+
+func TestClock(t *testing.T) {
+	is := assert.New(t)
+
+	frozen := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	errors.SetClock(func() time.Time {
+		return frozen
+	})
+	defer errors.SetClock(nil)
+
+	var err *errors.Error
+	is.ErrorAs(errors.Wrap(errors.Wrap(errors.New("invalid token"))), &err)
+	is.Equal(frozen, err.Time())
+	is.Equal(frozen, err.Unwrap().(*errors.Error).Time())
+}
+
+func TestRedacted(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(errors.WithMetadata("RefreshToken", "outer-token").WithAttribute("refreshToken", 42).Wrap(f()), &err)
+	is.Same(err, err.Redacted())
+
+	errors.SetRedactedKeys("refreshToken")
+	defer errors.SetRedactedKeys()
+
+	redacted := err.Redacted()
+	is.Equal(map[string]string{"refreshToken": errors.RedactedValue}, redacted.Metadata())
+	is.Equal([]errors.FieldViolation{{Field: "refreshToken", Description: errors.RedactedValue}}, redacted.FieldViolations())
+	is.Equal(errors.RedactedValue, redacted.MergedMetadata()["RefreshToken"])
+	is.Equal(errors.RedactedValue, redacted.Detach().Attributes()["refreshToken"])
+	is.Equal(err.Error(), redacted.Error())
+	is.Equal(map[string]string{"refreshToken": "refresh-token-string"}, err.Metadata())
+
+	log := func() string {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+		logger.Error("request failed", slog.Any("err", f()))
+		return buf.String()
+	}
+	is.Contains(log(), "refresh-token-string")
+
+	errors.SetRedactInLogValue(true)
+	defer errors.SetRedactInLogValue(false)
+	is.NotContains(log(), "refresh-token-string")
+	is.Contains(log(), `"refreshToken":"[REDACTED]"`)
+
+	t.Run("foreign wrapper", func(t *testing.T) {
+		is := assert.New(t)
+
+		var err *errors.Error
+		is.ErrorAs(errors.Wrap(fmt.Errorf("refresh: %w", f())), &err)
+		redacted := err.Redacted()
+		is.Equal(errors.RedactedValue, redacted.MergedMetadata()["refreshToken"])
+		is.Equal(err.Error(), redacted.Error())
+		is.Equal("refresh-token-string", err.MergedMetadata()["refreshToken"])
+	})
+
+	t.Run("sentinel", func(t *testing.T) {
+		is := assert.New(t)
+
+		errRefresh := errors.Reason("ERROR_REASON_INVALID_REFRESH_TOKEN").WithMetadata("refreshToken", "token").Error("invalid refresh token")
+
+		var err *errors.Error
+		is.ErrorAs(errors.Wrap(errRefresh), &err)
+
+		redacted := err.Redacted()
+		is.Equal(errors.RedactedValue, redacted.MergedMetadata()["refreshToken"])
+		is.ErrorIs(redacted, errRefresh)
+		is.ErrorIs(redacted, err)
+	})
+
+	t.Run("IDs", func(t *testing.T) {
+		is := assert.New(t)
+
+		var err *errors.Error
+		is.ErrorAs(errors.WithMetadata("refreshToken", "token").Wrap(fs.ErrClosed), &err)
+
+		redacted := err.Redacted()
+		is.Equal(*redacted.Trace(), *err.Trace())
+		is.Equal(*redacted.Span(), *err.Span())
+		is.Equal("token", err.Metadata()["refreshToken"])
+
+		is.ErrorAs(errors.WithMetadata("refreshToken", "token").Wrap(fs.ErrClosed), &err)
+		is.Equal(*err.Trace(), *err.Redacted().Trace())
+
+		is.ErrorAs(errors.Trace("trace").Wrap(fs.ErrClosed), &err)
+		is.Equal("trace", *err.Redacted().Trace())
+	})
+
+	t.Run("lazy IDs", func(t *testing.T) {
+		is := assert.New(t)
+
+		err := errors.Wrap(f())
+		var buf bytes.Buffer
+		slog.New(slog.NewJSONHandler(&buf, nil)).Error("request failed", slog.Any("err", err))
+
+		var record struct {
+			Err struct {
+				Trace string `json:"trace"`
+			} `json:"err"`
+		}
+		is.NoError(json.Unmarshal(buf.Bytes(), &record))
+		var e *errors.Error
+		is.ErrorAs(err, &e)
+		is.Equal(*e.Trace(), record.Err.Trace)
+	})
+}
+
+func TestAttributesLogValue(t *testing.T) {
+	is := assert.New(t)
+
+	err := errors.
+		WithMetadata("client", "web").
+		WithAttribute("client", "ios").
+		WithAttribute("attempts", 3).
+		Error("too many refreshes")
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Error("request failed", slog.Any("err", err))
+
+	var record struct {
+		Err struct {
+			Metadata map[string]any `json:"metadata"`
+		} `json:"err"`
+	}
+	is.NoError(json.Unmarshal(buf.Bytes(), &record))
+	is.Equal(map[string]any{"client": "web", "attempts": float64(3)}, record.Err.Metadata)
+}
+
+func TestGoString(t *testing.T) {
+	is := assert.New(t)
+
+	err := errors.WithMetadata("handler", "refresh").WithTag("http").Wrap(f())
+	want := `&errors.Error{Message:"Invalid refresh token", Reason:"ERROR_REASON_INVALID_REFRESH_TOKEN", Domain:"identity", ` +
+		`Metadata:map[string]string{"handler":"refresh", "refreshToken":"refresh-token-string"}, Tags:[]string{"http", "identity"}, FieldViolations:1}`
+	is.Equal(want, fmt.Sprintf("%#v", err))
+	is.Equal(want, err.(*errors.Error).GoString())
+	is.NotEqual(fmt.Sprintf("%+v", err), fmt.Sprintf("%#v", err))
+
+	is.Equal(`&errors.Error{Message:"invalid token"}`, fmt.Sprintf("%#v", errors.New("invalid token")))
+	is.Equal("(*errors.Error)(nil)", (*errors.Error)(nil).GoString())
+}
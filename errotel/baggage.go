@@ -0,0 +1,51 @@
+// Package errotel integrates errors with OpenTelemetry. It is kept apart from
+// the errors package so that users who don't need OpenTelemetry don't pull the
+// dependency.
+package errotel
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/baggage"
+
+	"github.com/notjustmoney/errors"
+)
+
+// BaggageMetadataPrefix is prepended to the key of every baggage member copied
+// into the metadata of an error.
+const BaggageMetadataPrefix = "baggage."
+
+var baggageKeys atomic.Pointer[[]string]
+
+// SetBaggageKeys restricts the baggage members copied by WithBaggage to the
+// given keys. Without keys, every member is copied, which is the default.
+func SetBaggageKeys(keys ...string) {
+	if len(keys) == 0 {
+		baggageKeys.Store(nil)
+		return
+	}
+	baggageKeys.Store(&keys)
+}
+
+// WithBaggage copies the baggage members of ctx into the metadata of the error
+// under the "baggage." prefix, so that the distributed context travels with the
+// error after the span has ended.
+func WithBaggage(ctx context.Context, b errors.ErrorBuilder) errors.ErrorBuilder {
+	bag := baggage.FromContext(ctx)
+
+	keys := baggageKeys.Load()
+	if keys == nil {
+		for _, member := range bag.Members() {
+			b = b.WithMetadata(BaggageMetadataPrefix+member.Key(), member.Value())
+		}
+		return b
+	}
+
+	for _, key := range *keys {
+		if member := bag.Member(key); member.Key() != "" {
+			b = b.WithMetadata(BaggageMetadataPrefix+member.Key(), member.Value())
+		}
+	}
+	return b
+}
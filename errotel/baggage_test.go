@@ -0,0 +1,35 @@
+package errotel_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/baggage"
+
+	"github.com/notjustmoney/errors"
+	"github.com/notjustmoney/errors/errotel"
+)
+
+func TestWithBaggage(t *testing.T) {
+	is := assert.New(t)
+
+	bag, err := baggage.Parse("tenant=acme,session=abc123")
+	is.NoError(err)
+	ctx := baggage.ContextWithBaggage(context.Background(), bag)
+
+	var e *errors.Error
+	is.ErrorAs(errotel.WithBaggage(ctx, errors.Reason("NOT_FOUND")).Error("not found"), &e)
+	is.Equal(map[string]string{
+		"baggage.tenant":  "acme",
+		"baggage.session": "abc123",
+	}, e.Metadata())
+
+	errotel.SetBaggageKeys("tenant", "region")
+	defer errotel.SetBaggageKeys()
+
+	is.ErrorAs(errotel.WithBaggage(ctx, errors.Reason("NOT_FOUND")).Error("not found"), &e)
+	is.Equal(map[string]string{
+		"baggage.tenant": "acme",
+	}, e.Metadata())
+}
@@ -0,0 +1,65 @@
+package errotel
+
+import (
+	stderrors "errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/notjustmoney/errors"
+)
+
+// AttributePrefix is prepended to the key of every span attribute set by
+// RecordOnSpan.
+const AttributePrefix = "error."
+
+// RecordOnSpan records err on span and sets the span status to Error. The
+// fields of an *errors.Error are set as span attributes under the "error."
+// prefix: reason, domain, each metadata entry, user and tenant IDs, request
+// ID, span kind and the field violations as a list of "field: description".
+func RecordOnSpan(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+
+	var e *errors.Error
+	if !stderrors.As(err, &e) {
+		return
+	}
+	span.SetAttributes(spanAttributes(e)...)
+}
+
+func spanAttributes(e *errors.Error) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	appendString := func(key string, value *string) {
+		if value != nil {
+			attrs = append(attrs, attribute.String(AttributePrefix+key, *value))
+		}
+	}
+
+	appendString("reason", e.Reason())
+	appendString("domain", e.Domain())
+	for key, value := range e.Metadata() {
+		attrs = append(attrs, attribute.String(AttributePrefix+"metadata."+key, value))
+	}
+	appendString("user_id", e.UserID())
+	appendString("tenant_id", e.TenantID())
+	appendString("request_id", e.RequestID())
+	if kind := e.SpanKind(); kind != errors.SpanKindUnspecified {
+		attrs = append(attrs, attribute.String(AttributePrefix+"span_kind", kind.String()))
+	}
+
+	if fieldViolations := e.FieldViolations(); len(fieldViolations) > 0 {
+		violations := make([]string, len(fieldViolations))
+		for i, violation := range fieldViolations {
+			violations[i] = violation.Field + ": " + violation.Description
+		}
+		attrs = append(attrs, attribute.StringSlice(AttributePrefix+"field_violations", violations))
+	}
+
+	return attrs
+}
@@ -0,0 +1,20 @@
+package errotel
+
+import (
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/notjustmoney/errors"
+)
+
+// FromSpanContext sets the trace and span of the error to the hex trace ID and
+// span ID of sc, so that the error correlates with the actual trace. Since the
+// trace is then set, it takes precedence over the random one Trace generates
+// lazily. An invalid span context leaves the builder unchanged.
+func FromSpanContext(sc trace.SpanContext, b errors.ErrorBuilder) errors.ErrorBuilder {
+	if !sc.IsValid() {
+		return b
+	}
+	return b.
+		Trace(sc.TraceID().String()).
+		Span(sc.SpanID().String())
+}
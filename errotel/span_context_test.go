@@ -0,0 +1,29 @@
+package errotel_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/notjustmoney/errors"
+	"github.com/notjustmoney/errors/errotel"
+)
+
+func TestFromSpanContext(t *testing.T) {
+	is := assert.New(t)
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:  trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+	})
+
+	var e *errors.Error
+	is.ErrorAs(errotel.FromSpanContext(sc, errors.Reason("NOT_FOUND")).Error("not found"), &e)
+	is.Equal("4bf92f3577b34da6a3ce929d0e0e4736", *e.Trace())
+	is.Equal("00f067aa0ba902b7", *e.Span())
+
+	is.ErrorAs(errotel.FromSpanContext(trace.SpanContext{}, errors.Reason("NOT_FOUND")).Error("not found"), &e)
+	is.Nil(e.Span())
+	is.NotEqual("00000000000000000000000000000000", *e.Trace())
+}
@@ -0,0 +1,72 @@
+package errotel_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/notjustmoney/errors"
+	"github.com/notjustmoney/errors/errotel"
+)
+
+type recordingSpan struct {
+	noop.Span
+
+	errs        []error
+	code        codes.Code
+	description string
+	attrs       map[attribute.Key]attribute.Value
+}
+
+func (s *recordingSpan) RecordError(err error, _ ...trace.EventOption) {
+	s.errs = append(s.errs, err)
+}
+
+func (s *recordingSpan) SetStatus(code codes.Code, description string) {
+	s.code, s.description = code, description
+}
+
+func (s *recordingSpan) SetAttributes(kv ...attribute.KeyValue) {
+	if s.attrs == nil {
+		s.attrs = map[attribute.Key]attribute.Value{}
+	}
+	for _, attr := range kv {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+
+func TestRecordOnSpan(t *testing.T) {
+	is := assert.New(t)
+
+	err := errors.
+		Reason("ERROR_REASON_INVALID_ARGUMENT").
+		Domain("identity").
+		WithMetadata("client", "web").
+		UserID("user-42").
+		TenantID("acme").
+		WithFieldViolation("email", "required").
+		Error("invalid argument")
+
+	span := &recordingSpan{}
+	errotel.RecordOnSpan(span, err)
+
+	is.Equal([]error{err}, span.errs)
+	is.Equal(codes.Error, span.code)
+	is.Equal("invalid argument", span.description)
+	is.Equal("ERROR_REASON_INVALID_ARGUMENT", span.attrs["error.reason"].AsString())
+	is.Equal("identity", span.attrs["error.domain"].AsString())
+	is.Equal("web", span.attrs["error.metadata.client"].AsString())
+	is.Equal("user-42", span.attrs["error.user_id"].AsString())
+	is.Equal("acme", span.attrs["error.tenant_id"].AsString())
+	is.Equal([]string{"email: required"}, span.attrs["error.field_violations"].AsStringSlice())
+
+	span = &recordingSpan{}
+	errotel.RecordOnSpan(span, fs.ErrNotExist)
+	is.Equal(codes.Error, span.code)
+	is.Empty(span.attrs)
+}
@@ -0,0 +1,57 @@
+// Package errprom counts errors with Prometheus. It is kept apart from the
+// errors package so that users who don't need Prometheus don't pull the
+// dependency.
+package errprom
+
+import (
+	stderrors "errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/notjustmoney/errors"
+)
+
+// unknown is the label value of a missing reason, domain or code, so that
+// unlabeled errors share a single series.
+const unknown = "unknown"
+
+// ErrorsTotal counts the errors observed by ObserveError by reason, domain and
+// code.
+var ErrorsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "errors_total",
+		Help: "Number of errors by reason, domain and code.",
+	},
+	[]string{"reason", "domain", "code"},
+)
+
+// Register registers ErrorsTotal with r.
+func Register(r prometheus.Registerer) error {
+	return r.Register(ErrorsTotal)
+}
+
+// ObserveError increments ErrorsTotal for err. Errors that are not
+// *errors.Error are counted with every label set to "unknown", as is the code
+// of an *errors.Error without one.
+func ObserveError(err error) {
+	if err == nil {
+		return
+	}
+
+	reason, domain, code := unknown, unknown, unknown
+
+	var e *errors.Error
+	if stderrors.As(err, &e) {
+		if r := e.Reason(); r != nil && *r != "" {
+			reason = *r
+		}
+		if d := e.Domain(); d != nil && *d != "" {
+			domain = *d
+		}
+		if c := e.Code(); c != errors.CodeUnknown {
+			code = c.String()
+		}
+	}
+
+	ErrorsTotal.WithLabelValues(reason, domain, code).Inc()
+}
@@ -0,0 +1,36 @@
+package errprom_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/notjustmoney/errors"
+	"github.com/notjustmoney/errors/errprom"
+)
+
+func TestObserveError(t *testing.T) {
+	is := assert.New(t)
+
+	registry := prometheus.NewRegistry()
+	is.NoError(errprom.Register(registry))
+	errprom.ErrorsTotal.Reset()
+
+	err := errors.
+		Reason("ERROR_REASON_INVALID_ARGUMENT").
+		Domain("identity").
+		Code(errors.CodeInvalidArgument).
+		Error("invalid argument")
+	errprom.ObserveError(err)
+	errprom.ObserveError(errors.Wrap(err))
+	errprom.ObserveError(fs.ErrNotExist)
+	errprom.ObserveError(errors.Wrap(fs.ErrNotExist))
+	errprom.ObserveError(nil)
+
+	is.Equal(2.0, testutil.ToFloat64(errprom.ErrorsTotal.WithLabelValues("ERROR_REASON_INVALID_ARGUMENT", "identity", "INVALID_ARGUMENT")))
+	is.Equal(2.0, testutil.ToFloat64(errprom.ErrorsTotal.WithLabelValues("unknown", "unknown", "unknown")))
+	is.Equal(2, testutil.CollectAndCount(registry, "errors_total"))
+}
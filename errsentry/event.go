@@ -0,0 +1,112 @@
+// Package errsentry converts errors to Sentry events. It is kept apart from the
+// errors package so that users who don't need Sentry don't pull the dependency.
+package errsentry
+
+import (
+	stderrors "errors"
+	"reflect"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/samber/lo"
+
+	"github.com/notjustmoney/errors"
+)
+
+// ToSentryEvent returns the Sentry event of err. For an *errors.Error, the
+// reason is the exception type and the fingerprint, so that issues are grouped
+// by reason, the frames are the stack trace, the metadata and violations are
+// contexts, the user and tenant IDs are the user, and the tags, reason, domain
+// and code are tags. Any other error produces a minimal event holding its type
+// and message.
+func ToSentryEvent(err error) *sentry.Event {
+	if err == nil {
+		return nil
+	}
+
+	event := sentry.NewEvent()
+	event.Level = sentry.LevelError
+
+	var e *errors.Error
+	if !stderrors.As(err, &e) {
+		event.Exception = []sentry.Exception{{
+			Type:  reflect.TypeOf(err).String(),
+			Value: err.Error(),
+		}}
+		return event
+	}
+
+	exception := sentry.Exception{
+		Type:  reflect.TypeOf(e).String(),
+		Value: e.Error(),
+	}
+	if reason := e.Reason(); reason != nil {
+		exception.Type = *reason
+		event.Fingerprint = []string{*reason}
+	}
+	if frames := e.Frames(); len(frames) > 0 {
+		exception.Stacktrace = &sentry.Stacktrace{Frames: toSentryFrames(frames)}
+	}
+	event.Exception = []sentry.Exception{exception}
+
+	event.Contexts = contexts(e)
+
+	event.User = sentry.User{ID: lo.FromPtr(e.UserID())}
+	if tenantID := e.TenantID(); tenantID != nil {
+		event.User.Data = map[string]string{"tenant_id": *tenantID}
+	}
+
+	for _, tag := range e.Tags() {
+		event.Tags[tag] = "true"
+	}
+	if reason := e.Reason(); reason != nil {
+		event.Tags["reason"] = *reason
+	}
+	if domain := e.Domain(); domain != nil {
+		event.Tags["domain"] = *domain
+	}
+	event.Tags["code"] = e.Code().String()
+
+	return event
+}
+
+// toSentryFrames converts frames, which are ordered from the innermost call,
+// to Sentry frames, which are ordered from the outermost call.
+func toSentryFrames(frames []errors.Frame) []sentry.Frame {
+	sentryFrames := make([]sentry.Frame, len(frames))
+	for i, frame := range frames {
+		sentryFrames[len(frames)-1-i] = sentry.Frame{
+			Function: frame.Function,
+			Filename: frame.File,
+			AbsPath:  frame.File,
+			Lineno:   frame.Line,
+			InApp:    true,
+		}
+	}
+	return sentryFrames
+}
+
+func contexts(e *errors.Error) map[string]sentry.Context {
+	contexts := map[string]sentry.Context{}
+
+	if metadata := e.Metadata(); len(metadata) > 0 {
+		contexts["metadata"] = lo.MapValues(metadata, func(value string, _ string) any {
+			return value
+		})
+	}
+
+	violations := sentry.Context{}
+	if quotaViolations := e.QuotaViolations(); len(quotaViolations) > 0 {
+		violations["quota"] = quotaViolations
+	}
+	if preconditionViolations := e.PreconditionViolations(); len(preconditionViolations) > 0 {
+		violations["precondition"] = preconditionViolations
+	}
+	if fieldViolations := e.FieldViolations(); len(fieldViolations) > 0 {
+		violations["field"] = fieldViolations
+	}
+	if len(violations) > 0 {
+		contexts["violations"] = violations
+	}
+
+	return contexts
+}
@@ -0,0 +1,50 @@
+package errsentry_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/notjustmoney/errors"
+	"github.com/notjustmoney/errors/errsentry"
+)
+
+func TestToSentryEvent(t *testing.T) {
+	is := assert.New(t)
+
+	err := errors.
+		Reason("ERROR_REASON_INVALID_ARGUMENT").
+		Domain("identity").
+		WithMetadata("client", "web").
+		UserID("user-42").
+		TenantID("acme").
+		WithTag("signup").
+		WithFieldViolation("email", "required").
+		Error("invalid argument")
+
+	event := errsentry.ToSentryEvent(err)
+	is.Equal(sentry.LevelError, event.Level)
+	is.Equal([]string{"ERROR_REASON_INVALID_ARGUMENT"}, event.Fingerprint)
+	is.Len(event.Exception, 1)
+	is.Equal("ERROR_REASON_INVALID_ARGUMENT", event.Exception[0].Type)
+	is.Equal("invalid argument", event.Exception[0].Value)
+	is.NotEmpty(event.Exception[0].Stacktrace.Frames)
+	frames := event.Exception[0].Stacktrace.Frames
+	is.Equal("TestToSentryEvent", frames[len(frames)-1].Function)
+	is.Equal(sentry.Context{"client": "web"}, event.Contexts["metadata"])
+	is.Equal([]errors.FieldViolation{{Field: "email", Description: "required"}}, event.Contexts["violations"]["field"])
+	is.Equal(sentry.User{ID: "user-42", Data: map[string]string{"tenant_id": "acme"}}, event.User)
+	is.Equal("true", event.Tags["signup"])
+	is.Equal("identity", event.Tags["domain"])
+}
+
+func TestToSentryEventOpaqueError(t *testing.T) {
+	is := assert.New(t)
+
+	event := errsentry.ToSentryEvent(fs.ErrNotExist)
+	is.Equal([]sentry.Exception{{Type: "*errors.errorString", Value: "file does not exist"}}, event.Exception)
+	is.Empty(event.Tags)
+	is.Nil(errsentry.ToSentryEvent(nil))
+}
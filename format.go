@@ -0,0 +1,59 @@
+package errors
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+var (
+	verboseIndent        atomic.Pointer[string]
+	verboseShowCauseType atomic.Bool
+)
+
+// SetVerboseIndent sets the string used for one level of indentation in the
+// %+v output, e.g. two spaces for log viewers that render tabs poorly. The
+// default is a tab.
+func SetVerboseIndent(indent string) {
+	verboseIndent.Store(&indent)
+}
+
+func getVerboseIndent() string {
+	indent := verboseIndent.Load()
+	if indent == nil {
+		return "\t"
+	}
+	return *indent
+}
+
+// SetVerboseShowCauseType sets whether the %+v output includes the Go type of
+// the root cause when it is not an *Error, e.g. *net.OpError, to tell which
+// library produced it. It is disabled by default.
+func SetVerboseShowCauseType(show bool) {
+	verboseShowCauseType.Store(show)
+}
+
+// foreignCause returns the innermost cause of the chain of e, following both
+// Unwrap and the Cause() method of other error libraries, or nil if it is an
+// *Error or the chain splits into several branches.
+func foreignCause(e *Error) error {
+	var err error = e
+	for {
+		var next error
+		switch x := err.(type) {
+		case *joinError, interface{ Unwrap() []error }:
+			return nil
+		case interface{ Cause() error }:
+			next = x.Cause()
+		default:
+			next = errors.Unwrap(err)
+		}
+
+		if next == nil || next == err {
+			if _, ok := err.(*Error); ok {
+				return nil
+			}
+			return err
+		}
+		err = next
+	}
+}
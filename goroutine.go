@@ -0,0 +1,37 @@
+package errors
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+)
+
+var captureGoroutineID atomic.Bool
+
+// SetCaptureGoroutineID enables recording the ID of the goroutine that creates
+// an error, to correlate errors with goroutine dumps. Reading the ID has a cost,
+// so it is disabled by default.
+func SetCaptureGoroutineID(enabled bool) {
+	captureGoroutineID.Store(enabled)
+}
+
+// currentGoroutineID returns the ID of the calling goroutine when capture is
+// enabled, or 0. The ID is parsed from the header of runtime.Stack, which is
+// "goroutine 123 [running]:".
+func currentGoroutineID() uint64 {
+	if !captureGoroutineID.Load() {
+		return 0
+	}
+
+	var buf [64]byte
+	header := buf[:runtime.Stack(buf[:], false)]
+	header = bytes.TrimPrefix(header, []byte("goroutine "))
+	header, _, _ = bytes.Cut(header, []byte(" "))
+
+	id, err := strconv.ParseUint(string(header), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
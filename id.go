@@ -0,0 +1,29 @@
+package errors
+
+import (
+	"sync/atomic"
+
+	"github.com/google/uuid"
+)
+
+var idGenerator atomic.Pointer[func() string]
+
+// SetIDGenerator sets the function that generates the trace and span IDs of
+// errors that have none, e.g. to use sortable identifiers such as ULIDs, or
+// deterministic ones in tests. A nil generator restores the default, which
+// generates random UUIDs.
+func SetIDGenerator(generator func() string) {
+	if generator == nil {
+		idGenerator.Store(nil)
+		return
+	}
+	idGenerator.Store(&generator)
+}
+
+func newID() string {
+	generator := idGenerator.Load()
+	if generator == nil {
+		return uuid.NewString()
+	}
+	return (*generator)()
+}
@@ -0,0 +1,57 @@
+package errors
+
+// The interfaces below are the stable contract for consumers of error details.
+// Generic code should type-assert against them instead of depending on the
+// concrete *Error, so that errors produced by other packages can participate as
+// long as they expose the same methods.
+
+// Coder is implemented by errors that carry a canonical code.
+type Coder interface {
+	Code() Code
+}
+
+// Reasoner is implemented by errors that carry a machine-readable reason.
+type Reasoner interface {
+	Reason() *string
+}
+
+// Domainer is implemented by errors that carry the domain of their reason.
+type Domainer interface {
+	Domain() *string
+}
+
+// MetadataCarrier is implemented by errors that carry key-value metadata.
+type MetadataCarrier interface {
+	Metadata() map[string]string
+}
+
+// Tagger is implemented by errors that carry tags.
+type Tagger interface {
+	Tags() []string
+}
+
+// Retrier is implemented by errors that carry retry information.
+type Retrier interface {
+	Retry() Retry
+}
+
+// Localizer is implemented by errors that carry localized messages.
+type Localizer interface {
+	Localizations() []Localization
+}
+
+// StackTracer is implemented by errors that carry a stack trace.
+type StackTracer interface {
+	StackTrace() string
+}
+
+var (
+	_ Coder           = (*Error)(nil)
+	_ Reasoner        = (*Error)(nil)
+	_ Domainer        = (*Error)(nil)
+	_ MetadataCarrier = (*Error)(nil)
+	_ Tagger          = (*Error)(nil)
+	_ Retrier         = (*Error)(nil)
+	_ Localizer       = (*Error)(nil)
+	_ StackTracer     = (*Error)(nil)
+)
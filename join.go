@@ -0,0 +1,37 @@
+package errors
+
+import (
+	"strings"
+)
+
+// joinError holds the branches of an error built with Join. Unlike the one of
+// the standard library, its branches are all followed by the accessors of this
+// package.
+type joinError struct {
+	errs []error
+}
+
+func newJoinError(errs []error) *joinError {
+	var nonNil []error
+	for _, err := range errs {
+		if err != nil {
+			nonNil = append(nonNil, err)
+		}
+	}
+	if len(nonNil) == 0 {
+		return nil
+	}
+	return &joinError{errs: nonNil}
+}
+
+func (j *joinError) Error() string {
+	messages := make([]string, len(j.errs))
+	for i, err := range j.errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+func (j *joinError) Unwrap() []error {
+	return j.errs
+}
@@ -0,0 +1,329 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/samber/lo"
+)
+
+// jsonError is the JSON shape of one layer of the chain. The cause is nested as
+// an object when it is an *Error, and as its message otherwise.
+type jsonError struct {
+	Message                *string                 `json:"message,omitempty"`
+	Code                   Code                    `json:"code,omitempty"`
+	Severity               Severity                `json:"severity,omitempty"`
+	HTTPStatus             *int                    `json:"httpStatus,omitempty"`
+	Reason                 *string                 `json:"reason,omitempty"`
+	Domain                 *string                 `json:"domain,omitempty"`
+	Metadata               map[string]string       `json:"metadata,omitempty"`
+	Attributes             map[string]any          `json:"attributes,omitempty"`
+	QuotaViolations        []QuotaViolation        `json:"quotaViolations,omitempty"`
+	PreconditionViolations []PreconditionViolation `json:"preconditionViolations,omitempty"`
+	FieldViolations        []FieldViolation        `json:"fieldViolations,omitempty"`
+	UserID                 *string                 `json:"userId,omitempty"`
+	TenantID               *string                 `json:"tenantId,omitempty"`
+	Trace                  *string                 `json:"trace,omitempty"`
+	Span                   *string                 `json:"span,omitempty"`
+	SpanKind               SpanKind                `json:"spanKind,omitempty"`
+	RequestID              *string                 `json:"requestId,omitempty"`
+	Tags                   []string                `json:"tags,omitempty"`
+	Time                   *time.Time              `json:"time,omitempty"`
+	Help                   *Help                   `json:"help,omitempty"`
+	Resource               *Resource               `json:"resource,omitempty"`
+	Localizations          []Localization          `json:"localizations,omitempty"`
+	LocalizationKey        *string                 `json:"localizationKey,omitempty"`
+	Retry                  *Retry                  `json:"retry,omitempty"`
+	Reported               bool                    `json:"reported,omitempty"`
+	StackTrace             *string                 `json:"stackTrace,omitempty"`
+	Cause                  json.RawMessage         `json:"cause,omitempty"`
+}
+
+// MarshalJSON encodes the error layer by layer. The branches of Join are encoded
+// as an array, and causes that are not *Error as their message.
+func (e *Error) MarshalJSON() ([]byte, error) {
+	if e == nil {
+		return []byte("null"), nil
+	}
+	je, err := e.toJSON(stackTraceFrame{})
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(je)
+}
+
+func (e *Error) toJSON(topFrame stackTraceFrame) (jsonError, error) {
+	je := jsonError{
+		Message:                e.message,
+		Code:                   e.code,
+		Severity:               e.severity,
+		HTTPStatus:             e.httpStatus,
+		Reason:                 e.reason,
+		Domain:                 e.domain,
+		Metadata:               truncateMetadata(e.metadata),
+		Attributes:             jsonAttributes(e.attributes),
+		QuotaViolations:        e.quotaViolations,
+		PreconditionViolations: e.preconditionViolations,
+		FieldViolations:        e.fieldViolations,
+		UserID:                 e.userID,
+		TenantID:               e.tenantID,
+		Trace:                  e.loadTrace(),
+		Span:                   e.loadSpan(),
+		SpanKind:               e.spanKind,
+		RequestID:              e.requestID,
+		Tags:                   e.tags,
+		Time:                   lo.EmptyableToPtr(e.time),
+		Help:                   lo.EmptyableToPtr(e.help),
+		Resource:               lo.EmptyableToPtr(e.resource),
+		Localizations:          e.localizations,
+		LocalizationKey:        e.localizationKey,
+		Retry:                  lo.EmptyableToPtr(e.retry),
+		Reported:               e.reported,
+	}
+
+	// The stack trace is kept as rendered text, trimmed like in StackTrace, so
+	// that it can be restored as an imported stack trace.
+	var stackTraces []string
+	if e.importedStackTrace != nil {
+		stackTraces = append(stackTraces, *e.importedStackTrace)
+	}
+	if len(e.stackTrace) > 0 {
+		stackTraces = append(stackTraces, e.stackTrace.StringUntilFrame(topFrame))
+		topFrame = e.stackTrace[0]
+	}
+	if len(stackTraces) > 0 {
+		je.StackTrace = lo.ToPtr(strings.Join(stackTraces, "\n"))
+	}
+
+	// The cause of Errorf is not repeated, as its text is already the message.
+	var err error
+	if e.err != nil && !e.messageFromCause {
+		je.Cause, err = causeToJSON(e.err, topFrame)
+	}
+	return je, err
+}
+
+// jsonAttributes returns the attributes with the values that can't be encoded
+// as JSON, such as channels or functions, replaced with their fmt.Sprint text,
+// so that one bad value doesn't fail the whole error.
+func jsonAttributes(attributes map[string]any) map[string]any {
+	if len(attributes) == 0 {
+		return nil
+	}
+	encodable := make(map[string]any, len(attributes))
+	for key, value := range attributes {
+		if _, err := json.Marshal(value); err != nil {
+			value = fmt.Sprint(value)
+		}
+		encodable[key] = value
+	}
+	return encodable
+}
+
+// causeToJSON encodes a cause as an object if it is an *Error, as an array if it
+// was built with Join, and as its message otherwise.
+func causeToJSON(cause error, topFrame stackTraceFrame) (json.RawMessage, error) {
+	switch cause := cause.(type) {
+	case *Error:
+		nested, err := cause.toJSON(topFrame)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(nested)
+	case *joinError:
+		branches := make([]json.RawMessage, len(cause.errs))
+		for i, branch := range cause.errs {
+			var err error
+			if branches[i], err = causeToJSON(branch, topFrame); err != nil {
+				return nil, err
+			}
+		}
+		return json.Marshal(branches)
+	default:
+		return json.Marshal(cause.Error())
+	}
+}
+
+// UnmarshalJSON decodes an error encoded by MarshalJSON. Nested causes are
+// rebuilt as *Error values and opaque causes as plain errors carrying their
+// message. Stack traces are restored as imported stack traces.
+func (e *Error) UnmarshalJSON(data []byte) error {
+	var je jsonError
+	if err := json.Unmarshal(data, &je); err != nil {
+		return err
+	}
+
+	*e = Error{
+		message:                je.Message,
+		code:                   je.Code,
+		severity:               je.Severity,
+		httpStatus:             je.HTTPStatus,
+		reason:                 je.Reason,
+		domain:                 je.Domain,
+		metadata:               je.Metadata,
+		attributes:             je.Attributes,
+		quotaViolations:        je.QuotaViolations,
+		preconditionViolations: je.PreconditionViolations,
+		fieldViolations:        je.FieldViolations,
+		userID:                 je.UserID,
+		tenantID:               je.TenantID,
+		trace:                  je.Trace,
+		span:                   je.Span,
+		spanKind:               je.SpanKind,
+		requestID:              je.RequestID,
+		tags:                   je.Tags,
+		time:                   lo.FromPtr(je.Time),
+		help:                   lo.FromPtr(je.Help),
+		resource:               lo.FromPtr(je.Resource),
+		localizations:          je.Localizations,
+		localizationKey:        je.LocalizationKey,
+		retry:                  lo.FromPtr(je.Retry),
+		reported:               je.Reported,
+		importedStackTrace:     je.StackTrace,
+		lazy:                   &lazyState{},
+	}
+
+	cause, err := causeFromJSON(je.Cause)
+	if err != nil {
+		return err
+	}
+	e.err = cause
+	return nil
+}
+
+func causeFromJSON(data json.RawMessage) (error, error) {
+	data = bytes.TrimSpace(data)
+	switch {
+	case len(data) == 0 || bytes.Equal(data, []byte("null")):
+		return nil, nil
+	case data[0] == '{':
+		nested := &Error{}
+		if err := nested.UnmarshalJSON(data); err != nil {
+			return nil, err
+		}
+		return nested, nil
+	case data[0] == '[':
+		var branches []json.RawMessage
+		if err := json.Unmarshal(data, &branches); err != nil {
+			return nil, err
+		}
+		errs := make([]error, len(branches))
+		for i, branch := range branches {
+			var err error
+			if errs[i], err = causeFromJSON(branch); err != nil {
+				return nil, err
+			}
+		}
+		return newJoinError(errs), nil
+	default:
+		var message string
+		if err := json.Unmarshal(data, &message); err != nil {
+			return nil, err
+		}
+		return errors.New(message), nil
+	}
+}
+
+// MarshalOptions customizes the output of MarshalJSONWith.
+type MarshalOptions struct {
+	// FieldNames overrides keys by their default path, such as "reason",
+	// "userId" or "fieldViolations.field". Nested causes use the same paths as
+	// the outermost error.
+	FieldNames map[string]string
+	// KeyFunc, if set, derives the keys that FieldNames does not override from
+	// their default name, e.g. SnakeCase.
+	KeyFunc func(string) string
+	// OmitStackTrace drops the stack trace of every layer, for payloads sent
+	// outside of the service.
+	OmitStackTrace bool
+}
+
+// MarshalJSONWith encodes the error like MarshalJSON, with the keys renamed
+// and the stack traces omitted according to opts. The metadata and attribute
+// keys and the localization argument names are never renamed.
+func (e *Error) MarshalJSONWith(opts MarshalOptions) ([]byte, error) {
+	data, err := e.MarshalJSON()
+	if err != nil || (len(opts.FieldNames) == 0 && opts.KeyFunc == nil && !opts.OmitStackTrace) {
+		return data, err
+	}
+
+	var v any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(opts.rename(v, ""))
+}
+
+// verbatimPaths are the paths whose keys are supplied by the user, and so are
+// never renamed: the metadata and attribute keys, and the localization argument
+// names, which must keep matching the placeholders of their message.
+var verbatimPaths = map[string]bool{
+	"metadata":           true,
+	"attributes":         true,
+	"localizations.args": true,
+}
+
+func (o MarshalOptions) rename(v any, path string) any {
+	switch v := v.(type) {
+	case map[string]any:
+		if verbatimPaths[path] {
+			return v
+		}
+		renamed := make(map[string]any, len(v))
+		for key, child := range v {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			if key == "cause" {
+				// A nested cause has the same shape as the outermost error.
+				renamed[o.key(childPath, key)] = o.rename(child, "")
+				continue
+			}
+			if key == "stackTrace" && path == "" && o.OmitStackTrace {
+				continue
+			}
+			renamed[o.key(childPath, key)] = o.rename(child, childPath)
+		}
+		return renamed
+	case []any:
+		return lo.Map(v, func(item any, _ int) any {
+			return o.rename(item, path)
+		})
+	default:
+		return v
+	}
+}
+
+func (o MarshalOptions) key(path string, key string) string {
+	if name, ok := o.FieldNames[path]; ok {
+		return name
+	}
+	if o.KeyFunc != nil {
+		return o.KeyFunc(key)
+	}
+	return key
+}
+
+// SnakeCase converts a camelCase key to snake_case.
+func SnakeCase(key string) string {
+	var sb strings.Builder
+	for i, r := range key {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				sb.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
@@ -0,0 +1,194 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/notjustmoney/errors"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	is := assert.New(t)
+
+	for name, source := range map[string]error{
+		"chain":     a(),
+		"generated": errors.Wrap(errors.UserID("user").TenantID("tenant").Wrap(fs.ErrNotExist)),
+	} {
+		var want *errors.Error
+		is.ErrorAs(source, &want, name)
+
+		// Marshaling only reads: the IDs generated afterwards are not in the
+		// output.
+		data, err := json.Marshal(want)
+		is.NoError(err, name)
+		if name == "generated" {
+			is.NotContains(string(data), *want.Trace(), name)
+			is.NotContains(string(data), *want.Span(), name)
+		}
+
+		data, err = json.Marshal(want)
+		is.NoError(err, name)
+
+		got := &errors.Error{}
+		is.NoError(json.Unmarshal(data, got), name)
+
+		is.Equal(want.Error(), got.Error(), name)
+		is.Equal(want.Message(), got.Message(), name)
+		is.Equal(want.Code(), got.Code(), name)
+		is.Equal(want.Reason(), got.Reason(), name)
+		is.Equal(want.Domain(), got.Domain(), name)
+		is.Equal(want.Metadata(), got.Metadata(), name)
+		is.ElementsMatch(want.QuotaViolations(), got.QuotaViolations(), name)
+		is.ElementsMatch(want.PreconditionViolations(), got.PreconditionViolations(), name)
+		is.ElementsMatch(want.FieldViolations(), got.FieldViolations(), name)
+		is.Equal(want.UserID(), got.UserID(), name)
+		is.Equal(want.TenantID(), got.TenantID(), name)
+		is.Equal(want.Trace(), got.Trace(), name)
+		is.Equal(want.Span(), got.Span(), name)
+		is.Equal(want.RequestID(), got.RequestID(), name)
+		is.ElementsMatch(want.Tags(), got.Tags(), name)
+		is.True(want.Time().Equal(got.Time()), name)
+		is.Equal(want.Help(), got.Help(), name)
+		is.Equal(want.Resource(), got.Resource(), name)
+		is.ElementsMatch(want.Localizations(), got.Localizations(), name)
+		is.Equal(want.Retry(), got.Retry(), name)
+		is.Equal(want.StackTrace(), got.StackTrace(), name)
+	}
+}
+
+func TestJSONOpaqueCause(t *testing.T) {
+	is := assert.New(t)
+
+	data, err := json.Marshal(errors.Reason("ERROR_REASON_NOT_FOUND").Wrap(fs.ErrNotExist))
+	is.NoError(err)
+
+	got := &errors.Error{}
+	is.NoError(json.Unmarshal(data, got))
+	is.Equal("ERROR_REASON_NOT_FOUND", *got.Reason())
+	is.Equal("file does not exist", got.Unwrap().Error())
+}
+
+func TestMaxMetadataValueLength(t *testing.T) {
+	is := assert.New(t)
+
+	errors.SetMaxMetadataValueLength(5)
+	defer errors.SetMaxMetadataValueLength(0)
+
+	var err *errors.Error
+	is.ErrorAs(errors.WithMetadata("payload", "0123456789").WithMetadata("id", "42").Error("failed"), &err)
+
+	is.Equal("0123456789", err.Metadata()["payload"])
+	is.Contains(fmt.Sprintf("%+v", err), "payload: 01234…\n")
+
+	data, marshalErr := json.Marshal(err)
+	is.NoError(marshalErr)
+	is.Contains(string(data), `"metadata":{"id":"42","payload":"01234…"}`)
+}
+
+func TestMarshalJSONWith(t *testing.T) {
+	is := assert.New(t)
+
+	err := errors.
+		Reason("ERROR_REASON_INVALID_REFRESH_TOKEN").
+		UserID("user").
+		WithMetadata("refreshToken", "token").
+		WithAttribute("retryCount", 3).
+		WithLocalization(errors.Localization{Locale: "en", Message: "Retry in {retryDelay}", Args: map[string]string{"retryDelay": "5s"}}).
+		WithFieldViolation("refreshToken", "expired").
+		Wrap(errors.WithQuotaViolation("user", "too many attempts").Error("invalid refresh token"))
+
+	var e *errors.Error
+	is.ErrorAs(err, &e)
+
+	data, marshalErr := e.MarshalJSONWith(errors.MarshalOptions{
+		FieldNames: map[string]string{
+			"reason":                  "error_code",
+			"fieldViolations.field":   "field_name",
+			"quotaViolations.subject": "quota_subject",
+		},
+		KeyFunc: errors.SnakeCase,
+	})
+	is.NoError(marshalErr)
+
+	var v map[string]any
+	is.NoError(json.Unmarshal(data, &v))
+	is.Equal("ERROR_REASON_INVALID_REFRESH_TOKEN", v["error_code"])
+	is.Equal("user", v["user_id"])
+	is.Equal(map[string]any{"refreshToken": "token"}, v["metadata"])
+	is.Equal(map[string]any{"retryCount": float64(3)}, v["attributes"])
+	is.Equal([]any{map[string]any{"locale": "en", "message": "Retry in {retryDelay}", "args": map[string]any{"retryDelay": "5s"}}}, v["localizations"])
+	is.Equal([]any{map[string]any{"field_name": "refreshToken", "description": "expired"}}, v["field_violations"])
+
+	cause, ok := v["cause"].(map[string]any)
+	is.True(ok)
+	is.Equal([]any{map[string]any{"quota_subject": "user", "description": "too many attempts"}}, cause["quota_violations"])
+}
+
+func TestMarshalProblemJSON(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(
+		errors.
+			Reason("ERROR_REASON_INVALID_REFRESH_TOKEN").
+			WithFieldViolation("refreshToken", "expired").
+			Error("invalid refresh token"),
+		&err,
+	)
+
+	data, marshalErr := err.MarshalProblemJSON()
+	is.NoError(marshalErr)
+	is.JSONEq(`{
+		"type": "about:blank",
+		"title": "ERROR_REASON_INVALID_REFRESH_TOKEN",
+		"status": 500,
+		"detail": "invalid refresh token",
+		"errors": [{"field": "refreshToken", "description": "expired"}]
+	}`, string(data))
+}
+
+func TestJSONJoinedCause(t *testing.T) {
+	is := assert.New(t)
+
+	data, err := json.Marshal(errors.Join(errors.WithTag("first").Wrap(fs.ErrExist), fs.ErrNotExist))
+	is.NoError(err)
+
+	got := &errors.Error{}
+	is.NoError(json.Unmarshal(data, got))
+	is.ElementsMatch([]string{"first"}, got.Tags())
+	is.Equal("file already exists\nfile does not exist", got.Unwrap().Error())
+}
+
+func TestJSONAttributes(t *testing.T) {
+	is := assert.New(t)
+
+	var want *errors.Error
+	is.ErrorAs(
+		errors.
+			WithMetadata("client", "web").
+			WithAttribute("attempts", 3).
+			WithAttribute("retryable", true).
+			WithAttribute("limits", map[string]int{"daily": 100}).
+			WithAttribute("done", make(chan struct{})).
+			Error("too many refreshes"),
+		&want,
+	)
+
+	data, err := json.Marshal(want)
+	is.NoError(err)
+	is.Contains(string(data), `"attempts":3`)
+	is.Contains(string(data), `"retryable":true`)
+	is.Contains(string(data), `"limits":{"daily":100}`)
+	is.Contains(string(data), `"done":"0x`)
+
+	got := &errors.Error{}
+	is.NoError(json.Unmarshal(data, got))
+	is.Equal(want.Metadata(), got.Metadata())
+	is.Equal(float64(3), got.Attributes()["attempts"])
+	is.Equal(true, got.Attributes()["retryable"])
+	is.Equal(map[string]any{"daily": float64(100)}, got.Attributes()["limits"])
+}
@@ -0,0 +1,165 @@
+package errors
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"github.com/samber/lo"
+)
+
+var defaultLocale atomic.Pointer[string]
+
+// SetDefaultLocale sets the locale Localize falls back to after the locale and
+// the fallbacks passed to it, e.g. "en" to always get an English message when
+// one exists. An empty locale disables the fallback, which is the default.
+func SetDefaultLocale(locale string) {
+	if locale == "" {
+		defaultLocale.Store(nil)
+		return
+	}
+	defaultLocale.Store(&locale)
+}
+
+// Localize returns the message that best matches locale. The localization key
+// is resolved against the catalog first, then the inline localizations are
+// searched. A locale that has no exact match falls back to its parent tags, so
+// "ko-KR" is answered by "ko", and then the fallbacks are tried in order,
+// followed by the default locale set with SetDefaultLocale.
+//
+//	message, ok := err.Localize("ko-KR", "en")
+func (e *Error) Localize(locale string, fallbacks ...string) (string, bool) {
+	message, args, ok := e.localize(locale, fallbacks)
+	if !ok {
+		return "", false
+	}
+	return expandPlaceholders(message, func(name string) (string, bool) {
+		value, ok := args[name]
+		return value, ok
+	}), true
+}
+
+// Localizef is like Localize but fills the placeholders of the message from
+// data before the Args of the localization. data is a map with string keys or
+// a struct, whose exported fields are looked up by name ignoring case. Placeholders that match
+// neither are left as is.
+//
+//	message, ok := err.Localizef("en", map[string]any{"count": 3})
+func (e *Error) Localizef(locale string, data any, fallbacks ...string) (string, bool) {
+	message, args, ok := e.localize(locale, fallbacks)
+	if !ok {
+		return "", false
+	}
+	return expandPlaceholders(message, func(name string) (string, bool) {
+		if value, ok := lookupPlaceholder(data, name); ok {
+			return value, true
+		}
+		value, ok := args[name]
+		return value, ok
+	}), true
+}
+
+func (e *Error) localize(locale string, fallbacks []string) (string, map[string]string, bool) {
+	key := e.LocalizationKey()
+	localizations := e.Localizations()
+
+	locales := append([]string{locale}, fallbacks...)
+	if l := defaultLocale.Load(); l != nil {
+		locales = append(locales, *l)
+	}
+
+	for _, candidate := range localeCandidates(locales) {
+		if key != nil {
+			if message, ok := getCatalog().lookup(*key, candidate); ok {
+				return message, nil, true
+			}
+		}
+
+		if l, ok := lo.Find(localizations, func(l Localization) bool {
+			return strings.EqualFold(normalizeLocale(l.Locale), candidate)
+		}); ok {
+			return l.Message, l.Args, true
+		}
+	}
+
+	return "", nil, false
+}
+
+var placeholderPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+// expandPlaceholders replaces each {name} in message with the value lookup
+// returns for name, leaving the placeholders it has no value for unchanged.
+func expandPlaceholders(message string, lookup func(name string) (string, bool)) string {
+	if !strings.Contains(message, "{") {
+		return message
+	}
+	return placeholderPattern.ReplaceAllStringFunc(message, func(placeholder string) string {
+		if value, ok := lookup(placeholder[1 : len(placeholder)-1]); ok {
+			return value
+		}
+		return placeholder
+	})
+}
+
+func lookupPlaceholder(data any, name string) (string, bool) {
+	switch data := data.(type) {
+	case nil:
+		return "", false
+	case map[string]string:
+		value, ok := data[name]
+		return value, ok
+	case map[string]any:
+		value, ok := data[name]
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprint(value), true
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(data))
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return "", false
+		}
+		value := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+		if !value.IsValid() {
+			return "", false
+		}
+		return fmt.Sprint(value.Interface()), true
+	case reflect.Struct:
+		field, ok := v.Type().FieldByNameFunc(func(field string) bool {
+			return strings.EqualFold(field, name)
+		})
+		if !ok || !field.IsExported() {
+			return "", false
+		}
+		return fmt.Sprint(v.FieldByIndex(field.Index).Interface()), true
+	default:
+		return "", false
+	}
+}
+
+// localeCandidates expands each locale into itself followed by its parent
+// tags, e.g. "zh-Hant-TW" into "zh-Hant-TW", "zh-Hant" and "zh".
+func localeCandidates(locales []string) []string {
+	var candidates []string
+	for _, locale := range locales {
+		locale = normalizeLocale(locale)
+		for locale != "" {
+			candidates = append(candidates, locale)
+			i := strings.LastIndex(locale, "-")
+			if i < 0 {
+				break
+			}
+			locale = locale[:i]
+		}
+	}
+	return lo.Uniq(candidates)
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ReplaceAll(strings.TrimSpace(locale), "_", "-")
+}
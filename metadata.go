@@ -0,0 +1,39 @@
+package errors
+
+import (
+	"sync/atomic"
+
+	"github.com/samber/lo"
+)
+
+var maxMetadataValueLength atomic.Int64
+
+// SetMaxMetadataValueLength caps the length, in runes, of the metadata values
+// written by LogValue, MarshalJSON and %+v. Longer values are truncated with an
+// ellipsis, while Metadata still returns them in full. Zero, the default, means
+// unlimited.
+func SetMaxMetadataValueLength(n int) {
+	maxMetadataValueLength.Store(int64(max(n, 0)))
+}
+
+func truncateMetadataValue(value string) string {
+	n := int(maxMetadataValueLength.Load())
+	if n == 0 {
+		return value
+	}
+
+	runes := []rune(value)
+	if len(runes) <= n {
+		return value
+	}
+	return string(runes[:n]) + "…"
+}
+
+func truncateMetadata(metadata map[string]string) map[string]string {
+	if maxMetadataValueLength.Load() == 0 || metadata == nil {
+		return metadata
+	}
+	return lo.MapValues(metadata, func(value string, _ string) string {
+		return truncateMetadataValue(value)
+	})
+}
@@ -0,0 +1,69 @@
+package errors
+
+// Policy declares how a service classifies its errors in one place. Rules are
+// evaluated in order and the first rule matching any layer of the chain is
+// applied.
+type Policy struct {
+	Rules []Rule
+}
+
+// Rule applies Action to the errors for which Match returns true. Match is
+// called with every layer of the chain, from the outermost to the innermost.
+type Rule struct {
+	Match  func(*Error) bool
+	Action func(ErrorBuilder) ErrorBuilder
+}
+
+// MatchReason matches the layers with the given reason.
+func MatchReason(reason string) func(*Error) bool {
+	return func(e *Error) bool {
+		return e.reason != nil && *e.reason == reason
+	}
+}
+
+// MatchCode matches the layers with the given code.
+func MatchCode(code Code) func(*Error) bool {
+	return func(e *Error) bool {
+		return e.code == code
+	}
+}
+
+// MatchDomain matches the layers with the given domain.
+func MatchDomain(domain string) func(*Error) bool {
+	return func(e *Error) bool {
+		return e.domain != nil && *e.domain == domain
+	}
+}
+
+// MatchTag matches the layers tagged with the given tag.
+func MatchTag(tag string) func(*Error) bool {
+	return func(e *Error) bool {
+		for _, t := range e.tags {
+			if t == tag {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Apply returns a copy of err with the action of the first matching rule
+// applied to its innermost layer, where the accessors resolve. The original
+// error is not modified, and err is returned as is when no rule matches.
+func (p Policy) Apply(err *Error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	for _, rule := range p.Rules {
+		var matched bool
+		recursive(err, func(e *Error) {
+			matched = matched || rule.Match(e)
+		})
+		if matched {
+			return mapInnermost(err, rule.Action)
+		}
+	}
+
+	return err
+}
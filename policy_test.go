@@ -0,0 +1,101 @@
+package errors_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/notjustmoney/errors"
+)
+
+func TestPolicy(t *testing.T) {
+	is := assert.New(t)
+
+	policy := errors.Policy{
+		Rules: []errors.Rule{
+			{
+				Match: errors.MatchReason("ERROR_REASON_UNAVAILABLE"),
+				Action: func(b errors.ErrorBuilder) errors.ErrorBuilder {
+					return b.Retry(errors.Retry{Delay: time.Second})
+				},
+			},
+			{
+				Match: errors.MatchDomain("identity"),
+				Action: func(b errors.ErrorBuilder) errors.ErrorBuilder {
+					return b.WithTag("security")
+				},
+			},
+		},
+	}
+
+	var err *errors.Error
+	is.ErrorAs(errors.Span("span").Wrap(a()), &err)
+
+	applied := policy.Apply(err)
+	is.Equal([]string{"identity", "security"}, applied.Tags())
+	is.Equal("span", *applied.Detach().Span())
+	is.Equal(err.StackTrace(), applied.StackTrace())
+	is.Equal([]string{"identity"}, err.Tags())
+
+	is.ErrorAs(errors.Reason("ERROR_REASON_UNAVAILABLE").Error("unavailable"), &err)
+	is.Equal(time.Second, policy.Apply(err).Retry().Delay)
+
+	is.ErrorAs(errors.Wrap(errors.Wrap(errors.Reason("ERROR_REASON_UNAVAILABLE").Error("unavailable"))), &err)
+	is.Equal(time.Second, policy.Apply(err).Retry().Delay)
+	is.Zero(err.Retry().Delay)
+
+	is.ErrorAs(errors.New("unknown"), &err)
+	is.Same(err, policy.Apply(err))
+}
+
+func TestPolicyWrapped(t *testing.T) {
+	is := assert.New(t)
+
+	policy := errors.Policy{
+		Rules: []errors.Rule{
+			{
+				Match: errors.MatchReason("ERROR_REASON_UNAVAILABLE"),
+				Action: func(b errors.ErrorBuilder) errors.ErrorBuilder {
+					return b.HTTPStatus(http.StatusServiceUnavailable)
+				},
+			},
+		},
+	}
+
+	var err *errors.Error
+	is.ErrorAs(errors.Wrap(fmt.Errorf("upstream: %w", errors.Wrap(errors.Reason("ERROR_REASON_UNAVAILABLE").Error("unavailable")))), &err)
+
+	applied := policy.Apply(err)
+	is.Equal(http.StatusServiceUnavailable, applied.HTTPStatus())
+	is.Equal(err.Error(), applied.Error())
+	is.Equal(err.StackTrace(), applied.StackTrace())
+	is.NotEqual(http.StatusServiceUnavailable, err.HTTPStatus())
+}
+
+func TestPolicySentinel(t *testing.T) {
+	is := assert.New(t)
+
+	errUnavailable := errors.Reason("ERROR_REASON_UNAVAILABLE").Code(errors.CodeUnavailable).Error("unavailable")
+	policy := errors.Policy{
+		Rules: []errors.Rule{
+			{
+				Match: errors.MatchCode(errors.CodeUnavailable),
+				Action: func(b errors.ErrorBuilder) errors.ErrorBuilder {
+					return b.HTTPStatus(http.StatusBadGateway)
+				},
+			},
+		},
+	}
+
+	var err *errors.Error
+	is.ErrorAs(errors.Wrap(errUnavailable), &err)
+
+	applied := policy.Apply(err)
+	is.Equal(http.StatusBadGateway, applied.HTTPStatus())
+	is.ErrorIs(err, errUnavailable)
+	is.ErrorIs(applied, errUnavailable)
+	is.ErrorIs(applied, err)
+}
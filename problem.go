@@ -0,0 +1,41 @@
+package errors
+
+import (
+	"encoding/json"
+
+	"github.com/samber/lo"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem details.
+const ProblemContentType = "application/problem+json"
+
+// ProblemDetail is the RFC 7807 representation of an error, with the field
+// violations as the "errors" extension member.
+type ProblemDetail struct {
+	Type     string           `json:"type"`
+	Title    string           `json:"title,omitempty"`
+	Status   int              `json:"status"`
+	Detail   string           `json:"detail,omitempty"`
+	Instance string           `json:"instance,omitempty"`
+	Errors   []FieldViolation `json:"errors,omitempty"`
+}
+
+// ProblemDetails returns the RFC 7807 problem details of the error. The title is
+// the reason, the status is the HTTP status, the detail is the error message and
+// the instance is the request ID.
+func (e *Error) ProblemDetails() ProblemDetail {
+	return ProblemDetail{
+		Type:     "about:blank",
+		Title:    lo.FromPtr(e.Reason()),
+		Status:   e.HTTPStatus(),
+		Detail:   e.Error(),
+		Instance: lo.FromPtr(e.RequestID()),
+		Errors:   e.FieldViolations(),
+	}
+}
+
+// MarshalProblemJSON encodes the RFC 7807 problem details of the error, to be
+// written with the ProblemContentType media type.
+func (e *Error) MarshalProblemJSON() ([]byte, error) {
+	return json.Marshal(e.ProblemDetails())
+}
@@ -0,0 +1,74 @@
+package errors
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// RedactedValue replaces the redacted metadata values and field violation
+// descriptions.
+const RedactedValue = "[REDACTED]"
+
+var (
+	redactedKeys     atomic.Pointer[map[string]struct{}]
+	redactInLogValue atomic.Bool
+)
+
+// SetRedactedKeys sets the metadata keys and field violation fields whose
+// values Redacted replaces, e.g. "refreshToken". Keys are matched ignoring
+// case. Calling it without keys disables redaction, which is the default.
+func SetRedactedKeys(keys ...string) {
+	if len(keys) == 0 {
+		redactedKeys.Store(nil)
+		return
+	}
+	set := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		set[strings.ToLower(key)] = struct{}{}
+	}
+	redactedKeys.Store(&set)
+}
+
+// SetRedactInLogValue sets whether LogValue logs the Redacted copy of the
+// error. It is disabled by default.
+func SetRedactInLogValue(enabled bool) {
+	redactInLogValue.Store(enabled)
+}
+
+func isRedactedKey(key string) bool {
+	keys := redactedKeys.Load()
+	if keys == nil {
+		return false
+	}
+	_, ok := (*keys)[strings.ToLower(key)]
+	return ok
+}
+
+// Redacted returns a copy of the error in which, on every layer of the chain,
+// the metadata and attribute values and the field violation descriptions of the
+// keys set with SetRedactedKeys are replaced with RedactedValue. The copy
+// reports the same trace and span IDs as the original, which is not modified.
+// The error is returned as is when no keys are set.
+func (e *Error) Redacted() *Error {
+	if e == nil || redactedKeys.Load() == nil {
+		return e
+	}
+	return mapChain(e, func(e ErrorBuilder) ErrorBuilder {
+		for key := range e.metadata {
+			if isRedactedKey(key) {
+				e.metadata[key] = RedactedValue
+			}
+		}
+		for key := range e.attributes {
+			if isRedactedKey(key) {
+				e.attributes[key] = RedactedValue
+			}
+		}
+		for i, violation := range e.fieldViolations {
+			if isRedactedKey(violation.Field) {
+				e.fieldViolations[i].Description = RedactedValue
+			}
+		}
+		return e
+	})
+}
@@ -0,0 +1,32 @@
+package errors
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryFromHeader parses the value of an HTTP Retry-After header, either a
+// number of seconds or an HTTP date, into a retry policy. The delay of a date
+// is relative to the clock set with SetClock, and 0 if the date has passed. It
+// returns false if the value is malformed.
+func RetryFromHeader(value string) (Retry, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return Retry{}, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return Retry{}, false
+		}
+		return Retry{Delay: time.Duration(seconds) * time.Second}, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return Retry{}, false
+	}
+	return Retry{Delay: max(date.Sub(now()), 0)}, true
+}
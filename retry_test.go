@@ -0,0 +1,45 @@
+package errors_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/notjustmoney/errors"
+)
+
+func TestRetryFromHeader(t *testing.T) {
+	is := assert.New(t)
+
+	retry, ok := errors.RetryFromHeader("120")
+	is.True(ok)
+	is.Equal(errors.Retry{Delay: 2 * time.Minute}, retry)
+
+	retry, ok = errors.RetryFromHeader(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	is.True(ok)
+	is.InDelta(time.Hour, retry.Delay, float64(2*time.Second))
+
+	retry, ok = errors.RetryFromHeader("Wed, 21 Oct 2015 07:28:00 GMT")
+	is.True(ok)
+	is.Zero(retry.Delay)
+
+	for _, value := range []string{"", "-1", "soon", "1.5", "21 Oct 2015"} {
+		_, ok = errors.RetryFromHeader(value)
+		is.False(ok, value)
+	}
+
+	var err *errors.Error
+	is.ErrorAs(errors.RetryAfter(retry.Delay+time.Second).Error("unavailable"), &err)
+	is.Equal(errors.Retry{Delay: time.Second}, err.Retry())
+
+	errors.SetClock(func() time.Time {
+		return time.Date(2015, time.October, 21, 7, 0, 0, 0, time.UTC)
+	})
+	defer errors.SetClock(nil)
+
+	retry, ok = errors.RetryFromHeader("Wed, 21 Oct 2015 07:28:00 GMT")
+	is.True(ok)
+	is.Equal(errors.Retry{Delay: 28 * time.Minute}, retry)
+}
@@ -0,0 +1,79 @@
+package errors
+
+import (
+	"reflect"
+)
+
+// rewrapped stands for a wrapper of another package, such as the one of
+// fmt.Errorf with %w, whose cause was replaced by mapChain. It keeps the text of
+// the wrapper, which can't be rebuilt, and still matches it with Is and As.
+type rewrapped struct {
+	wrapper error
+	cause   error
+}
+
+func (r *rewrapped) Error() string {
+	return r.wrapper.Error()
+}
+
+func (r *rewrapped) Unwrap() error {
+	return r.cause
+}
+
+func (r *rewrapped) Is(target error) bool {
+	return isWrapper(r.wrapper, target)
+}
+
+func (r *rewrapped) As(target any) bool {
+	return asWrapper(r.wrapper, target)
+}
+
+// rewrappedJoin is the rewrapped of a wrapper with several branches, such as
+// errors.Join.
+type rewrappedJoin struct {
+	wrapper error
+	causes  []error
+}
+
+func (r *rewrappedJoin) Error() string {
+	return r.wrapper.Error()
+}
+
+func (r *rewrappedJoin) Unwrap() []error {
+	return r.causes
+}
+
+func (r *rewrappedJoin) Is(target error) bool {
+	return isWrapper(r.wrapper, target)
+}
+
+func (r *rewrappedJoin) As(target any) bool {
+	return asWrapper(r.wrapper, target)
+}
+
+func isWrapper(wrapper error, target error) bool {
+	if target != nil && reflect.TypeOf(target).Comparable() && wrapper == target {
+		return true
+	}
+	if x, ok := wrapper.(interface{ Is(error) bool }); ok {
+		return x.Is(target)
+	}
+	return false
+}
+
+// asWrapper assigns wrapper itself to target. The As method of the wrapper is
+// not consulted for *Error targets, as it would reach the replaced cause.
+func asWrapper(wrapper error, target any) bool {
+	if _, ok := target.(**Error); ok {
+		return false
+	}
+	val := reflect.ValueOf(target)
+	if val.Kind() == reflect.Pointer && !val.IsNil() && reflect.TypeOf(wrapper).AssignableTo(val.Type().Elem()) {
+		val.Elem().Set(reflect.ValueOf(wrapper))
+		return true
+	}
+	if x, ok := wrapper.(interface{ As(any) bool }); ok {
+		return x.As(target)
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package errors
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// Severity classifies how urgently an error needs attention, e.g. to route
+// fatal errors to pager alerts. The zero value is unset and reads as
+// SeverityError.
+type Severity int
+
+const (
+	SeverityInfo Severity = iota + 1
+	SeverityWarning
+	SeverityError
+	SeverityFatal
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	case SeverityFatal:
+		return "fatal"
+	default:
+		return "unspecified"
+	}
+}
+
+// Level returns the slog level for the severity. SeverityFatal maps to a level
+// above slog.LevelError.
+func (s Severity) Level() slog.Level {
+	switch s {
+	case SeverityInfo:
+		return slog.LevelInfo
+	case SeverityWarning:
+		return slog.LevelWarn
+	case SeverityFatal:
+		return slog.LevelError + 4
+	default:
+		return slog.LevelError
+	}
+}
+
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Severity) UnmarshalText(text []byte) error {
+	for severity := SeverityInfo; severity <= SeverityFatal; severity++ {
+		if severity.String() == string(text) {
+			*s = severity
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown severity %q", text)
+}
@@ -0,0 +1,125 @@
+package errors
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// The shortcuts below build the errors of the most common canonical codes in
+// one call. They are a high-level vocabulary over the builder and follow the
+// gRPC canonical codes.
+
+// InvalidArgument returns an error for an invalid request field.
+func InvalidArgument(field, description string) error {
+	return newBuilder().
+		Code(CodeInvalidArgument).
+		Reason("INVALID_ARGUMENT").
+		WithFieldViolation(field, description).
+		Error("invalid argument " + field + ": " + description)
+}
+
+// NotFound returns an error for a missing resource.
+func NotFound(resourceType, name string) error {
+	return newBuilder().
+		Code(CodeNotFound).
+		Reason("NOT_FOUND").
+		Resource(Resource{Type: resourceType, Name: name}).
+		Error(resourceType + " " + name + " not found")
+}
+
+// IsNotFound reports whether err is an *Error with code NotFound.
+func IsNotFound(err error) bool {
+	return hasCode(err, CodeNotFound)
+}
+
+// PermissionDenied returns an error for a subject the caller is not allowed to
+// access.
+func PermissionDenied(subject, description string) error {
+	return newBuilder().
+		Code(CodePermissionDenied).
+		Reason("PERMISSION_DENIED").
+		WithPreconditionViolation("PERMISSION", subject, description).
+		Error("permission denied on " + subject + ": " + description)
+}
+
+// IsPermissionDenied reports whether err is an *Error with code
+// PermissionDenied.
+func IsPermissionDenied(err error) bool {
+	return hasCode(err, CodePermissionDenied)
+}
+
+// AlreadyExists returns an error for a resource that conflicts with an existing
+// one.
+func AlreadyExists(resourceType, name string) error {
+	return newBuilder().
+		Code(CodeAlreadyExists).
+		Reason("ALREADY_EXISTS").
+		Resource(Resource{Type: resourceType, Name: name}).
+		Error(resourceType + " " + name + " already exists")
+}
+
+// IsAlreadyExists reports whether err is an *Error with code AlreadyExists.
+func IsAlreadyExists(err error) bool {
+	return hasCode(err, CodeAlreadyExists)
+}
+
+// Unauthenticated returns an error for a request without valid credentials.
+// An optional help link can point the caller at how to authenticate.
+func Unauthenticated(description string, help ...Help) error {
+	b := newBuilder().
+		Code(CodeUnauthenticated).
+		HTTPStatus(http.StatusUnauthorized).
+		Reason("UNAUTHENTICATED")
+	if len(help) > 0 {
+		b = b.Help(help[0])
+	}
+	return b.Error("unauthenticated: " + description)
+}
+
+// IsUnauthenticated reports whether err is an *Error with code
+// Unauthenticated.
+func IsUnauthenticated(err error) bool {
+	return hasCode(err, CodeUnauthenticated)
+}
+
+// ResourceExhausted returns an error for an exhausted quota, such as a rate
+// limit, together with the delay after which the request can be retried.
+func ResourceExhausted(subject, description string, retryAfter time.Duration) error {
+	return newBuilder().
+		Code(CodeResourceExhausted).
+		HTTPStatus(http.StatusTooManyRequests).
+		Reason("RESOURCE_EXHAUSTED").
+		WithQuotaViolation(subject, description).
+		Retry(Retry{Delay: retryAfter}).
+		Error("resource exhausted " + subject + ": " + description)
+}
+
+// IsResourceExhausted reports whether err is an *Error with code
+// ResourceExhausted.
+func IsResourceExhausted(err error) bool {
+	return hasCode(err, CodeResourceExhausted)
+}
+
+// FailedPrecondition returns an error for a request the system is not in a
+// state to accept, such as terms not accepted or an unverified account. It maps
+// to HTTP 412.
+func FailedPrecondition(typ, subject, description string) error {
+	return newBuilder().
+		Code(CodeFailedPrecondition).
+		HTTPStatus(http.StatusPreconditionFailed).
+		Reason("FAILED_PRECONDITION").
+		WithPreconditionViolation(typ, subject, description).
+		Error("failed precondition " + subject + ": " + description)
+}
+
+// IsFailedPrecondition reports whether err is an *Error with code
+// FailedPrecondition.
+func IsFailedPrecondition(err error) bool {
+	return hasCode(err, CodeFailedPrecondition)
+}
+
+func hasCode(err error, code Code) bool {
+	var e *Error
+	return errors.As(err, &e) && e.Code() == code
+}
@@ -0,0 +1,172 @@
+package errors_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/notjustmoney/errors"
+)
+
+func TestInvalidArgument(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(errors.InvalidArgument("email", "must not be empty"), &err)
+	is.Equal(errors.CodeInvalidArgument, err.Code())
+	is.Equal("INVALID_ARGUMENT", *err.Reason())
+	is.Equal([]errors.FieldViolation{{Field: "email", Description: "must not be empty"}}, err.FieldViolations())
+	is.Equal("invalid argument email: must not be empty", err.Error())
+
+	_, _, function := err.Origin()
+	is.Equal("TestInvalidArgument", function)
+}
+
+func TestShortcutCodes(t *testing.T) {
+	is := assert.New(t)
+
+	for code, err := range map[errors.Code]error{
+		errors.CodeNotFound:         errors.NotFound("user", "42"),
+		errors.CodePermissionDenied: errors.PermissionDenied("user/42", "not the owner"),
+		errors.CodeAlreadyExists:    errors.AlreadyExists("user", "42"),
+		errors.CodeUnauthenticated:  errors.Unauthenticated("missing token"),
+	} {
+		var e *errors.Error
+		is.ErrorAs(err, &e)
+		is.Equal(code, e.Code())
+		is.Equal(code.String(), *e.Reason())
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	is := assert.New(t)
+
+	err := errors.NotFound("user", "42")
+	is.True(errors.IsNotFound(err))
+	is.True(errors.IsNotFound(errors.Wrapf(err, "load profile")))
+	is.False(errors.IsNotFound(errors.AlreadyExists("user", "42")))
+	is.False(errors.IsNotFound(nil))
+
+	var e *errors.Error
+	is.ErrorAs(err, &e)
+	is.Equal(errors.Resource{Type: "user", Name: "42"}, e.Resource())
+	is.Equal("user 42 not found", e.Error())
+}
+
+func TestPermissionDenied(t *testing.T) {
+	is := assert.New(t)
+
+	err := errors.PermissionDenied("document/7", "only the owner can share the document")
+	is.True(errors.IsPermissionDenied(err))
+	is.True(errors.IsPermissionDenied(errors.Wrap(err)))
+	is.False(errors.IsPermissionDenied(errors.Unauthenticated("missing token")))
+
+	var e *errors.Error
+	is.ErrorAs(err, &e)
+	is.Equal([]errors.PreconditionViolation{{
+		Type:        "PERMISSION",
+		Subject:     "document/7",
+		Description: "only the owner can share the document",
+	}}, e.PreconditionViolations())
+}
+
+func TestAlreadyExists(t *testing.T) {
+	is := assert.New(t)
+
+	err := errors.AlreadyExists("user", "alice")
+	is.True(errors.IsAlreadyExists(err))
+	is.True(errors.IsAlreadyExists(errors.Wrap(err)))
+	is.False(errors.IsAlreadyExists(errors.NotFound("user", "alice")))
+
+	var e *errors.Error
+	is.ErrorAs(err, &e)
+	is.Equal("ALREADY_EXISTS", *e.Reason())
+	is.Equal(errors.Resource{Type: "user", Name: "alice"}, e.Resource())
+}
+
+func TestUnauthenticated(t *testing.T) {
+	is := assert.New(t)
+
+	err := errors.Unauthenticated("missing token")
+	is.True(errors.IsUnauthenticated(err))
+	is.False(errors.IsUnauthenticated(errors.PermissionDenied("user/42", "not the owner")))
+
+	var e *errors.Error
+	is.ErrorAs(err, &e)
+	is.Empty(e.Help())
+	is.Equal(http.StatusUnauthorized, e.HTTPStatus())
+
+	help := errors.Help{Description: "Sign in", URL: "https://example.com/login"}
+	is.ErrorAs(errors.Unauthenticated("expired token", help), &e)
+	is.Equal(help, e.Help())
+}
+
+func TestResourceExhausted(t *testing.T) {
+	is := assert.New(t)
+
+	err := errors.ResourceExhausted("user/42", "100 requests per minute", 30*time.Second)
+	is.True(errors.IsResourceExhausted(err))
+	is.False(errors.IsResourceExhausted(errors.NotFound("user", "42")))
+
+	var e *errors.Error
+	is.ErrorAs(err, &e)
+	is.Equal([]errors.QuotaViolation{{Subject: "user/42", Description: "100 requests per minute"}}, e.QuotaViolations())
+	is.Equal(errors.Retry{Delay: 30 * time.Second}, e.Retry())
+	is.Equal(http.StatusTooManyRequests, e.HTTPStatus())
+}
+
+func TestFailedPrecondition(t *testing.T) {
+	is := assert.New(t)
+
+	err := errors.FailedPrecondition("TOS", "user/42", "terms of service not accepted")
+	is.True(errors.IsFailedPrecondition(err))
+	is.False(errors.IsFailedPrecondition(errors.PermissionDenied("user/42", "not the owner")))
+
+	var e *errors.Error
+	is.ErrorAs(err, &e)
+	is.Equal([]errors.PreconditionViolation{{
+		Type:        "TOS",
+		Subject:     "user/42",
+		Description: "terms of service not accepted",
+	}}, e.PreconditionViolations())
+	is.Equal(http.StatusPreconditionFailed, e.HTTPStatus())
+}
+
+func TestCodeHTTPStatus(t *testing.T) {
+	is := assert.New(t)
+
+	for code, status := range map[errors.Code]int{
+		errors.CodeOK:                 http.StatusOK,
+		errors.CodeCancelled:          499,
+		errors.CodeUnknown:            http.StatusInternalServerError,
+		errors.CodeInvalidArgument:    http.StatusBadRequest,
+		errors.CodeDeadlineExceeded:   http.StatusGatewayTimeout,
+		errors.CodeNotFound:           http.StatusNotFound,
+		errors.CodeAlreadyExists:      http.StatusConflict,
+		errors.CodePermissionDenied:   http.StatusForbidden,
+		errors.CodeResourceExhausted:  http.StatusTooManyRequests,
+		errors.CodeFailedPrecondition: http.StatusBadRequest,
+		errors.CodeAborted:            http.StatusConflict,
+		errors.CodeOutOfRange:         http.StatusBadRequest,
+		errors.CodeUnimplemented:      http.StatusNotImplemented,
+		errors.CodeInternal:           http.StatusInternalServerError,
+		errors.CodeUnavailable:        http.StatusServiceUnavailable,
+		errors.CodeDataLoss:           http.StatusInternalServerError,
+		errors.CodeUnauthenticated:    http.StatusUnauthorized,
+		errors.Code("BOGUS"):          http.StatusInternalServerError,
+	} {
+		is.Equal(status, code.HTTPStatus(), code)
+	}
+
+	var err *errors.Error
+	is.ErrorAs(errors.New("invalid token"), &err)
+	is.Equal(http.StatusInternalServerError, err.HTTPStatus())
+
+	is.ErrorAs(errors.Wrap(errors.NotFound("user", "42")), &err)
+	is.Equal(http.StatusNotFound, err.HTTPStatus())
+
+	is.ErrorAs(errors.Wrap(errors.HTTPStatus(http.StatusGone).Reason("NOT_FOUND").Code(errors.CodeNotFound).Error("user 42 is gone")), &err)
+	is.Equal(http.StatusGone, err.HTTPStatus())
+}
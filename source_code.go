@@ -5,6 +5,7 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/samber/lo"
 )
@@ -15,6 +16,30 @@ var cache = map[string][]string{}
 const nbrLinesBefore = 5
 const nbrLinesAfter = 5
 
+type sourceContextLines struct {
+	before int
+	after  int
+}
+
+var sourceContext atomic.Pointer[sourceContextLines]
+
+// SetSourceContextLines sets how many lines before and after the failing line
+// Sources includes. Negative values are treated as 0. The default is 5 and 5.
+func SetSourceContextLines(before, after int) {
+	sourceContext.Store(&sourceContextLines{
+		before: max(before, 0),
+		after:  max(after, 0),
+	})
+}
+
+func getSourceContextLines() (before int, after int) {
+	lines := sourceContext.Load()
+	if lines == nil {
+		return nbrLinesBefore, nbrLinesAfter
+	}
+	return lines.before, lines.after
+}
+
 func readFile(path string) ([]string, bool) {
 	mutex.RLock()
 	lines, ok := cache[path]
@@ -44,7 +69,11 @@ func readFile(path string) ([]string, bool) {
 }
 
 func getSourceFromFrame(frame stackTraceFrame) []string {
-	lines, ok := readFile(frame.file)
+	path := frame.path
+	if path == "" {
+		path = frame.file
+	}
+	lines, ok := readFile(path)
 	if !ok {
 		return []string{}
 	}
@@ -53,9 +82,10 @@ func getSourceFromFrame(frame stackTraceFrame) []string {
 		return []string{}
 	}
 
+	before, after := getSourceContextLines()
 	current := frame.line - 1
-	start := lo.Max([]int{0, current - nbrLinesBefore})
-	end := lo.Min([]int{len(lines) - 1, current + nbrLinesAfter})
+	start := lo.Max([]int{0, current - before})
+	end := lo.Min([]int{len(lines) - 1, current + after})
 
 	output := []string{}
 
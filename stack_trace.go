@@ -6,8 +6,11 @@ import (
 	"path/filepath"
 	"reflect"
 	"runtime"
+	"runtime/debug"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 const (
@@ -18,42 +21,146 @@ const (
 var (
 	// packageName is the name of the package.
 	packageName = reflect.TypeOf(Error{}).PkgPath()
+
+	stackPathRedactor atomic.Pointer[func(string) string]
+
+	stackTraceDisabled atomic.Bool
+
+	trimPathPrefix atomic.Pointer[string]
+
+	collapseRepeatedFrames atomic.Bool
+
+	// readBuildInfo reads the build information once, as it never changes.
+	readBuildInfo = sync.OnceValues(debug.ReadBuildInfo)
 )
 
+// SetCollapseRepeatedFrames sets whether consecutive frames of the same function
+// in the same file, as in deep recursion, are rendered as a single frame
+// followed by "(xN)". It is disabled by default.
+func SetCollapseRepeatedFrames(collapse bool) {
+	collapseRepeatedFrames.Store(collapse)
+}
+
+// SetStackTraceEnabled sets whether errors capture a stack trace when they are
+// built. Disabling it saves the cost of walking the stack in hot paths, at the
+// price of empty StackTrace, Sources and Frames. It is enabled by default.
+func SetStackTraceEnabled(enabled bool) {
+	stackTraceDisabled.Store(!enabled)
+}
+
+// SetStackPathRedactor sets the function applied to the file path of every frame
+// when a stack trace is rendered, so that traces can be shipped without leaking
+// the server layout. A nil redactor restores the default, which keeps paths
+// unchanged.
+func SetStackPathRedactor(redactor func(string) string) {
+	if redactor == nil {
+		stackPathRedactor.Store(nil)
+		return
+	}
+	stackPathRedactor.Store(&redactor)
+}
+
+// KeepLastPathSegments returns a redactor that keeps only the last n segments
+// of a path.
+func KeepLastPathSegments(n int) func(string) string {
+	return func(path string) string {
+		segments := strings.Split(filepath.ToSlash(path), "/")
+		if len(segments) <= n {
+			return path
+		}
+		return strings.Join(segments[len(segments)-n:], "/")
+	}
+}
+
+func redactStackPath(path string) string {
+	redactor := stackPathRedactor.Load()
+	if redactor == nil {
+		return path
+	}
+	return (*redactor)(path)
+}
+
 type stackTrace []stackTraceFrame
 
-func newStacktrace() stackTrace {
-	var frames []stackTraceFrame
+// callersBufferSize is the number of program counters read at once when
+// capturing a stack trace, enough to reach StackTraceMaxDepth frames after the
+// frames of this package and of the runtime are skipped. Deeper stacks are read
+// again into larger buffers.
+const callersBufferSize = 256
 
-	// We loop until we have StackTraceMaxDepth frames or we run out of frames.
-	// Frames from this package are skipped.
-	for i := 0; len(frames) < StackTraceMaxDepth; i++ {
-		pc, file, line, ok := runtime.Caller(i)
-		if !ok {
-			break
+// callersPool recycles the program counter buffers of newStacktrace, which
+// only live until the frames are resolved.
+var callersPool = sync.Pool{
+	New: func() any {
+		pcs := make([]uintptr, callersBufferSize)
+		return &pcs
+	},
+}
+
+// newStacktrace captures up to maxDepth frames of the calling goroutine. The
+// first skip frames left after skipping the frames of this package are
+// dropped.
+func newStacktrace(maxDepth int, skip int) stackTrace {
+	if stackTraceDisabled.Load() || maxDepth <= 0 {
+		return nil
+	}
+
+	pcs := callersPool.Get().(*[]uintptr)
+	defer callersPool.Put(pcs)
+
+	buf := *pcs
+	for {
+		n := runtime.Callers(1, buf)
+		frames := resolveFrames(buf[:n], maxDepth, skip)
+		// A full buffer may have cut the stack short of maxDepth frames.
+		if len(frames) >= maxDepth || n < len(buf) {
+			return frames
 		}
-		file = removeGoPath(file)
+		buf = make([]uintptr, 2*len(buf))
+	}
+}
+
+// resolveFrames resolves up to maxDepth frames from the program counters,
+// skipping the frames of this package and of the runtime, then the first skip
+// frames left.
+func resolveFrames(pcs []uintptr, maxDepth int, skip int) stackTrace {
+	callers := runtime.CallersFrames(pcs)
 
-		f := runtime.FuncForPC(pc)
-		if f == nil {
+	var frames []stackTraceFrame
+
+	// We loop until we have maxDepth frames or we run out of frames.
+	// Frames from this package are skipped.
+	for len(frames) < maxDepth {
+		frame, more := callers.Next()
+		if frame.Function == "" {
 			break
 		}
-		function := shortenFuncName(f)
+		pc, file, line := frame.PC, frame.File, frame.Line
+		function := shortenFuncName(frame.Function)
 
 		packageNameExamples := packageName + "/examples/"
 
-		isGoPkg := len(runtime.GOROOT()) > 0 && strings.Contains(file, runtime.GOROOT()) // skip frames in GOROOT if it's set
-		isThisPkg := strings.Contains(file, packageName)                                 // skip frames in this package
-		isExamplePkg := strings.Contains(file, packageNameExamples)                      // do not skip frames in this package examples
-		isTestPkg := strings.Contains(file, "_test.go")                                  // do not skip frames in tests
+		isGoPkg := len(runtime.GOROOT()) > 0 && strings.Contains(file, runtime.GOROOT())  // skip frames in GOROOT if it's set
+		isThisPkg := strings.Contains(file, packageName) || isPackageFunc(frame.Function) // skip frames in this package
+		isExamplePkg := strings.Contains(file, packageNameExamples)                       // do not skip frames in this package examples
+		isTestPkg := strings.Contains(file, "_test.go")                                   // do not skip frames in tests
 
 		if !isGoPkg && (!isThisPkg || isExamplePkg || isTestPkg) {
-			frames = append(frames, stackTraceFrame{
-				pc:       pc,
-				file:     file,
-				function: function,
-				line:     line,
-			})
+			if skip > 0 {
+				skip--
+			} else {
+				frames = append(frames, stackTraceFrame{
+					pc:       pc,
+					file:     trimPath(file, frame.Function),
+					function: function,
+					line:     line,
+					path:     file,
+				})
+			}
+		}
+
+		if !more {
+			break
 		}
 	}
 
@@ -78,8 +185,19 @@ func (st stackTrace) Error() string {
 }
 
 func (st stackTrace) StringUntilFrame(deepestFrame stackTraceFrame) string {
-	var s string
-	for _, frame := range st {
+	var (
+		lines    []string
+		previous *stackTraceFrame
+		repeats  int
+	)
+	collapse := collapseRepeatedFrames.Load()
+	flush := func() {
+		if repeats > 1 {
+			lines[len(lines)-1] += fmt.Sprintf(" (x%d)", repeats)
+		}
+	}
+
+	for i, frame := range st {
 		if frame.file == "" {
 			continue
 		}
@@ -91,44 +209,91 @@ func (st stackTrace) StringUntilFrame(deepestFrame stackTraceFrame) string {
 		if frame.Equals(deepestFrame) {
 			break
 		}
-		if s != "" && !strings.HasSuffix(s, "\n") {
-			s += "\n"
+		if collapse && previous != nil && previous.file == frame.file && previous.function == frame.function {
+			repeats++
+			continue
 		}
-		s += "  --- at " + frameStr
+		flush()
+		lines = append(lines, "  --- at "+frameStr)
+		previous, repeats = &st[i], 1
 	}
-	return s
+	flush()
+
+	return strings.Join(lines, "\n")
+}
+
+// FramesUntilFrame returns the frames StringUntilFrame renders.
+func (st stackTrace) FramesUntilFrame(deepestFrame stackTraceFrame) []Frame {
+	var frames []Frame
+	for _, frame := range st {
+		if frame.file == "" {
+			continue
+		}
+		if frame.Equals(deepestFrame) {
+			break
+		}
+		frames = append(frames, frame.Frame())
+	}
+	return frames
 }
 
 func (st stackTrace) String() string {
 	return st.StringUntilFrame(stackTraceFrame{})
 }
 
+// Frame is a stack frame of an error.
+type Frame struct {
+	File     string
+	Line     int
+	Function string
+	PC       uintptr
+}
+
 type stackTraceFrame struct {
 	pc       uintptr
 	file     string
 	function string
 	line     int
+
+	// path is the untrimmed path of file, to read its source.
+	path string
 }
 
 func (f *stackTraceFrame) String() string {
-	s := fmt.Sprintf("%v:%v", f.file, f.line)
+	file := redactStackPath(f.file)
+	s := fmt.Sprintf("%v:%v", file, f.line)
 	if f.function != "" {
-		s = fmt.Sprintf("%v:%v %v()", f.file, f.line, f.function)
+		s = fmt.Sprintf("%v:%v %v()", file, f.line, f.function)
 	}
 
 	return s
 }
 
+func (f *stackTraceFrame) Frame() Frame {
+	return Frame{
+		File:     redactStackPath(f.file),
+		Line:     f.line,
+		Function: f.function,
+		PC:       f.pc,
+	}
+}
+
 func (f *stackTraceFrame) Equals(other stackTraceFrame) bool {
 	return f.file == other.file && f.function == other.function && f.line == other.line
 }
 
-func shortenFuncName(f *runtime.Func) string {
-	// f.Name() is like one of these:
+// isPackageFunc reports whether the fully qualified function name belongs to
+// this package or one of its sub-packages. Unlike the file path, the name does
+// not depend on where the sources are checked out.
+func isPackageFunc(name string) bool {
+	return strings.HasPrefix(name, packageName+".") || strings.HasPrefix(name, packageName+"/")
+}
+
+func shortenFuncName(longName string) string {
+	// longName is like one of these:
 	// - "github.com/palantir/shield/package.FuncName"
 	// - "github.com/palantir/shield/package.Receiver.MethodName"
 	// - "github.com/palantir/shield/package.(*PtrReceiver).MethodName"
-	longName := f.Name()
 
 	withoutPath := longName[strings.LastIndex(longName, "/")+1:]
 	withoutPackage := withoutPath[strings.Index(withoutPath, ".")+1:]
@@ -141,6 +306,82 @@ func shortenFuncName(f *runtime.Func) string {
 	return shortName
 }
 
+// SetTrimPath sets a prefix removed from the file path of the frames captured
+// from now on, for build setups where the module root can't be derived. An
+// empty prefix restores the default, which makes paths relative to the root of
+// the main module, or to $GOPATH/src in GOPATH mode.
+func SetTrimPath(prefix string) {
+	if prefix == "" {
+		trimPathPrefix.Store(nil)
+		return
+	}
+	trimPathPrefix.Store(&prefix)
+}
+
+// trimPath shortens the path of the file of the function named funcName.
+func trimPath(path string, funcName string) string {
+	if prefix := trimPathPrefix.Load(); prefix != nil {
+		if rel, ok := trimDir(path, *prefix); ok {
+			return rel
+		}
+	}
+	if rel, ok := trimModuleRoot(path, funcName); ok {
+		return rel
+	}
+	return removeGoPath(path)
+}
+
+/*
+trimModuleRoot makes a path relative to the root of the main module. The root is
+found by removing the directory of the function's package, relative to the
+module, from the directory of the file, so it works without a go.mod at runtime
+and for paths already rewritten by -trimpath.
+*/
+func trimModuleRoot(path string, funcName string) (string, bool) {
+	bi, ok := readBuildInfo()
+	if !ok || bi.Main.Path == "" {
+		return "", false
+	}
+	modulePath := bi.Main.Path
+
+	pkgPath := funcPackagePath(funcName)
+	if pkgPath == "main" {
+		pkgPath = bi.Path
+	}
+	pkgPath = strings.TrimSuffix(pkgPath, "_test")
+	if pkgPath != modulePath && !strings.HasPrefix(pkgPath, modulePath+"/") {
+		return "", false
+	}
+
+	dir := filepath.ToSlash(filepath.Dir(path))
+	pkgDir := strings.TrimPrefix(pkgPath, modulePath)
+	if !strings.HasSuffix(dir, pkgDir) {
+		return "", false
+	}
+	return trimDir(path, strings.TrimSuffix(dir, pkgDir))
+}
+
+// funcPackagePath returns the import path of the package of a fully qualified
+// function name, e.g. "github.com/a/b" for "github.com/a/b.(*T).Method".
+func funcPackagePath(funcName string) string {
+	lastSlash := strings.LastIndex(funcName, "/")
+	dot := strings.Index(funcName[lastSlash+1:], ".")
+	if dot < 0 {
+		return funcName
+	}
+	return funcName[:lastSlash+1+dot]
+}
+
+// trimDir removes dir from the beginning of path, if path is inside of it.
+func trimDir(path string, dir string) (string, bool) {
+	dir = strings.TrimSuffix(filepath.ToSlash(dir), "/") + "/"
+	slashed := filepath.ToSlash(path)
+	if !strings.HasPrefix(slashed, dir) {
+		return "", false
+	}
+	return slashed[len(dir):], true
+}
+
 /*
 removeGoPath makes a path relative to one of the src directories in the $GOPATH
 environment variable. If $GOPATH is empty or the input path is not contained
@@ -1,7 +1,9 @@
 package errors
 
 import (
-	"runtime/debug"
+	"fmt"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 
@@ -29,7 +31,7 @@ func e() stackTrace {
 }
 
 func f() stackTrace {
-	return newStacktrace()
+	return newStacktrace(StackTraceMaxDepth, 0)
 }
 
 func TestStackTrace(t *testing.T) {
@@ -37,26 +39,94 @@ func TestStackTrace(t *testing.T) {
 
 	st := a()
 
-	is.NotNil(st)
-	bi, ok := debug.ReadBuildInfo()
-	is.True(ok)
-
-	if st == nil {
+	is.Len(st, 7, "expected 7 frames")
+	if len(st) != 7 {
 		return
 	}
 
 	for _, frame := range st {
-		is.Truef(strings.Contains(frame.file, bi.Path), "frame file %s should contain %s", frame.file, bi.Path)
-		is.Len(st, 7, "expected 7 frames")
-		if len(st) != 7 {
-			return
-		}
-		is.Equal("f", st[0].function)
-		is.Equal("e", st[1].function)
-		is.Equal("d", st[2].function)
-		is.Equal("c", st[3].function)
-		is.Equal("b", st[4].function)
-		is.Equal("a", st[5].function)
-		is.Equal("TestStackTrace", st[6].function)
+		is.Equal("stack_trace_test.go", frame.file, "frame file should be relative to the module root")
+		is.True(filepath.IsAbs(frame.path))
+	}
+	is.Equal("f", st[0].function)
+	is.Equal("e", st[1].function)
+	is.Equal("d", st[2].function)
+	is.Equal("c", st[3].function)
+	is.Equal("b", st[4].function)
+	is.Equal("a", st[5].function)
+	is.Equal("TestStackTrace", st[6].function)
+}
+
+func TestStackPathRedactor(t *testing.T) {
+	is := assert.New(t)
+
+	SetStackPathRedactor(func(path string) string {
+		return "redacted/" + KeepLastPathSegments(1)(path)
+	})
+	defer SetStackPathRedactor(nil)
+
+	st := a()
+	is.NotEmpty(st)
+	for _, line := range strings.Split(st.String(), "\n") {
+		is.Regexp(`^  --- at redacted/[^/]+\.go:\d+ `, line)
+	}
+
+	is.Equal("errors/stack_trace.go", KeepLastPathSegments(2)("/root/go/src/errors/stack_trace.go"))
+	is.Equal("stack_trace.go", KeepLastPathSegments(2)("stack_trace.go"))
+}
+
+func TestTrimPath(t *testing.T) {
+	is := assert.New(t)
+
+	st := a()
+	is.NotEmpty(st)
+	is.Equal("stack_trace_test.go", st[0].file)
+	is.NotEmpty(getSourceFromFrame(st[0]))
+
+	_, file, _, _ := runtime.Caller(0)
+	SetTrimPath(filepath.Dir(filepath.Dir(file)))
+	defer SetTrimPath("")
+
+	st = a()
+	is.Equal(filepath.Base(filepath.Dir(file))+"/stack_trace_test.go", st[0].file)
+
+	is.Equal("github.com/notjustmoney/errors", funcPackagePath("github.com/notjustmoney/errors.(*Error).Error"))
+	is.Equal("main", funcPackagePath("main.main"))
+}
+
+func recurse(n int, maxDepth int) stackTrace {
+	if n == 0 {
+		return newStacktrace(maxDepth, 0)
+	}
+	return recurse(n-1, maxDepth)
+}
+
+func TestCollapseRepeatedFrames(t *testing.T) {
+	is := assert.New(t)
+
+	st := recurse(2*StackTraceMaxDepth, StackTraceMaxDepth)
+	is.Len(st, StackTraceMaxDepth)
+	is.Len(strings.Split(st.String(), "\n"), StackTraceMaxDepth)
+
+	SetCollapseRepeatedFrames(true)
+	defer SetCollapseRepeatedFrames(false)
+
+	is.Equal(fmt.Sprintf("  --- at %s (x%d)", st[0].String(), StackTraceMaxDepth), st.String())
+}
+
+func TestDeepStackTrace(t *testing.T) {
+	is := assert.New(t)
+
+	st := recurse(500, 1000)
+	is.Greater(len(st), 500)
+	is.Equal("TestDeepStackTrace", st[len(st)-1].function)
+
+	is.Len(recurse(500, 300), 300)
+}
+
+func BenchmarkNewStacktrace(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = newStacktrace(StackTraceMaxDepth, 0)
 	}
 }
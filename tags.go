@@ -0,0 +1,29 @@
+package errors
+
+import (
+	"sync/atomic"
+)
+
+// TagSortMode controls the order of the tags returned by Tags.
+type TagSortMode int32
+
+const (
+	// TagSortInsertion keeps the tags in the order they were first seen, from
+	// the outermost to the innermost error of the chain.
+	TagSortInsertion TagSortMode = iota
+	// TagSortAlphabetical sorts the tags alphabetically, which makes them
+	// stable across chains built in a different order.
+	TagSortAlphabetical
+)
+
+var tagSortMode atomic.Int32
+
+// SetTagSortMode sets the order of the tags returned by Tags. The default is
+// TagSortInsertion.
+func SetTagSortMode(mode TagSortMode) {
+	tagSortMode.Store(int32(mode))
+}
+
+func getTagSortMode() TagSortMode {
+	return TagSortMode(tagSortMode.Load())
+}
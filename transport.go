@@ -0,0 +1,36 @@
+package errors
+
+import (
+	"github.com/samber/lo"
+)
+
+// TransportVersion is the version of the TransportError shape. It is bumped on
+// any change that is not backwards compatible for remote peers.
+const TransportVersion = 1
+
+// TransportError is the minimal envelope sent to remote peers. It only holds
+// the fields a peer needs to act on the error.
+type TransportError struct {
+	Version                int                     `json:"version"`
+	Code                   Code                    `json:"code"`
+	Reason                 string                  `json:"reason,omitempty"`
+	Domain                 string                  `json:"domain,omitempty"`
+	Message                string                  `json:"message,omitempty"`
+	QuotaViolations        []QuotaViolation        `json:"quotaViolations,omitempty"`
+	PreconditionViolations []PreconditionViolation `json:"preconditionViolations,omitempty"`
+	FieldViolations        []FieldViolation        `json:"fieldViolations,omitempty"`
+}
+
+// TransportView returns the transport-relevant subset of the error.
+func (e *Error) TransportView() TransportError {
+	return TransportError{
+		Version:                TransportVersion,
+		Code:                   e.Code(),
+		Reason:                 lo.FromPtr(e.Reason()),
+		Domain:                 lo.FromPtr(e.Domain()),
+		Message:                e.Error(),
+		QuotaViolations:        e.QuotaViolations(),
+		PreconditionViolations: e.PreconditionViolations(),
+		FieldViolations:        e.FieldViolations(),
+	}
+}
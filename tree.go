@@ -0,0 +1,105 @@
+package errors
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Tree renders the whole error graph as an indented tree, including the
+// branches of joined errors. Each *Error node shows the reason and message it
+// was built with, followed by the fields that layer added, such as its domain,
+// metadata and tags; other errors show their message. A node that was already
+// visited higher up in the same branch is rendered as a cycle and not expanded
+// again.
+func (e *Error) Tree() string {
+	var sb strings.Builder
+	writeTree(&sb, e, "", "", map[*Error]bool{})
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func writeTree(sb *strings.Builder, err error, prefix string, childPrefix string, visiting map[*Error]bool) {
+	sb.WriteString(prefix)
+
+	ee, isError := err.(*Error)
+	if isError && visiting[ee] {
+		sb.WriteString(treeLabel(err))
+		sb.WriteString(" (cycle)\n")
+		return
+	}
+	sb.WriteString(treeLabel(err))
+	sb.WriteString("\n")
+
+	if isError {
+		visiting[ee] = true
+		defer delete(visiting, ee)
+	}
+
+	children := unwrapAll(err)
+	for i, child := range children {
+		if i == len(children)-1 {
+			writeTree(sb, child, childPrefix+"└── ", childPrefix+"    ", visiting)
+		} else {
+			writeTree(sb, child, childPrefix+"├── ", childPrefix+"│   ", visiting)
+		}
+	}
+}
+
+func treeLabel(err error) string {
+	ee, ok := err.(*Error)
+	if !ok {
+		if _, ok := err.(interface{ Unwrap() []error }); ok {
+			return "Join"
+		}
+		return err.Error()
+	}
+
+	var parts []string
+	if ee.reason != nil {
+		parts = append(parts, "["+*ee.reason+"]")
+	}
+	if ee.message != nil {
+		parts = append(parts, *ee.message)
+	}
+	if len(parts) == 0 {
+		parts = append(parts, "Error")
+	}
+	if fields := treeFields(ee); len(fields) > 0 {
+		parts = append(parts, "("+strings.Join(fields, ", ")+")")
+	}
+	return strings.Join(parts, " ")
+}
+
+// treeFields lists the fields set on the layer e itself.
+func treeFields(e *Error) []string {
+	var fields []string
+	if e.code != "" {
+		fields = append(fields, "code="+e.code.String())
+	}
+	if e.severity != 0 {
+		fields = append(fields, "severity="+e.severity.String())
+	}
+	if e.httpStatus != nil {
+		fields = append(fields, "httpStatus="+strconv.Itoa(*e.httpStatus))
+	}
+	if e.domain != nil {
+		fields = append(fields, "domain="+*e.domain)
+	}
+	for _, key := range slices.Sorted(maps.Keys(e.metadata)) {
+		fields = append(fields, "metadata."+key+"="+truncateMetadataValue(e.metadata[key]))
+	}
+	if len(e.tags) > 0 {
+		fields = append(fields, "tags="+strings.Join(e.tags, ","))
+	}
+	if n := len(e.quotaViolations); n > 0 {
+		fields = append(fields, "quotaViolations="+strconv.Itoa(n))
+	}
+	if n := len(e.preconditionViolations); n > 0 {
+		fields = append(fields, "preconditionViolations="+strconv.Itoa(n))
+	}
+	if n := len(e.fieldViolations); n > 0 {
+		fields = append(fields, "fieldViolations="+strconv.Itoa(n))
+	}
+	return fields
+}
@@ -0,0 +1,81 @@
+package errors_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/notjustmoney/errors"
+)
+
+func TestTree(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(
+		errors.Reason("ERROR_REASON_BATCH").Wrap(
+			errors.Join(
+				errors.Reason("ERROR_REASON_NOT_FOUND").Error("user not found"),
+				errors.Wrap(fs.ErrNotExist),
+			),
+		),
+		&err,
+	)
+
+	is.Equal(`[ERROR_REASON_BATCH]
+└── Error
+    └── Join
+        ├── [ERROR_REASON_NOT_FOUND] user not found
+        └── Error
+            └── file does not exist`, err.Tree())
+}
+
+func TestTreeLayerFields(t *testing.T) {
+	is := assert.New(t)
+
+	var err *errors.Error
+	is.ErrorAs(
+		errors.WithMetadata("handler", "refresh").WithTag("http").Wrap(
+			errors.
+				Reason("ERROR_REASON_INVALID_REFRESH_TOKEN").
+				Code(errors.CodeUnauthenticated).
+				Domain("identity").
+				WithMetadata("client", "web").
+				WithFieldViolation("refreshToken", "expired").
+				Wrap(fs.ErrNotExist),
+		),
+		&err,
+	)
+
+	is.Equal(`Error (metadata.handler=refresh, tags=http)
+└── [ERROR_REASON_INVALID_REFRESH_TOKEN] (code=UNAUTHENTICATED, domain=identity, metadata.client=web, fieldViolations=1)
+    └── file does not exist`, err.Tree())
+}
+
+func TestReasonCounts(t *testing.T) {
+	is := assert.New(t)
+
+	notFound := func() error {
+		return errors.Wrap(errors.Reason("NOT_FOUND").Error("not found"))
+	}
+
+	var err *errors.Error
+	is.ErrorAs(
+		errors.Reason("BATCH_FAILED").Wrap(
+			errors.Join(
+				notFound(),
+				errors.Reason("PERMISSION_DENIED").Error("permission denied"),
+				notFound(),
+				fs.ErrNotExist,
+			),
+		),
+		&err,
+	)
+
+	is.Equal(map[string]int{
+		"BATCH_FAILED":      1,
+		"NOT_FOUND":         2,
+		"PERMISSION_DENIED": 1,
+	}, err.ReasonCounts())
+}
@@ -1,34 +1,177 @@
 package errors
 
 import (
+	"fmt"
 	"log/slog"
+	"math"
 	"time"
+
+	"golang.org/x/text/language"
+)
+
+// SpanKind classifies where in a distributed call the error occurred. The
+// values match the OpenTelemetry span kinds.
+type SpanKind int
+
+const (
+	SpanKindUnspecified SpanKind = iota
+	SpanKindInternal
+	SpanKindServer
+	SpanKindClient
+	SpanKindProducer
+	SpanKindConsumer
+)
+
+func (k SpanKind) String() string {
+	switch k {
+	case SpanKindInternal:
+		return "internal"
+	case SpanKindServer:
+		return "server"
+	case SpanKindClient:
+		return "client"
+	case SpanKindProducer:
+		return "producer"
+	case SpanKindConsumer:
+		return "consumer"
+	default:
+		return "unspecified"
+	}
+}
+
+func (k SpanKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+func (k *SpanKind) UnmarshalText(text []byte) error {
+	for kind := SpanKindUnspecified; kind <= SpanKindConsumer; kind++ {
+		if kind.String() == string(text) {
+			*k = kind
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown span kind %q", text)
+}
+
+// BackoffStrategy defines how the delay between retries grows.
+type BackoffStrategy int
+
+const (
+	BackoffConstant BackoffStrategy = iota
+	BackoffLinear
+	BackoffExponential
 )
 
+func (s BackoffStrategy) String() string {
+	switch s {
+	case BackoffLinear:
+		return "linear"
+	case BackoffExponential:
+		return "exponential"
+	default:
+		return "constant"
+	}
+}
+
+func (s BackoffStrategy) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *BackoffStrategy) UnmarshalText(text []byte) error {
+	for strategy := BackoffConstant; strategy <= BackoffExponential; strategy++ {
+		if strategy.String() == string(text) {
+			*s = strategy
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown backoff strategy %q", text)
+}
+
 type Retry struct {
-	Delay time.Duration
+	// Delay is the delay before the first retry, and the base of the backoff.
+	Delay time.Duration `json:"delay"`
+	// MaxAttempts is the number of attempts after which the operation should
+	// not be retried anymore.
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+	// Attempt is the number of attempts already made.
+	Attempt int `json:"attempt,omitempty"`
+	// Strategy defines how the delay grows with the attempts.
+	Strategy BackoffStrategy `json:"strategy,omitempty"`
+	// Multiplier is the growth factor of the delay: the delay grows by Delay
+	// times Multiplier per attempt when linear, and is multiplied by it when
+	// exponential. It defaults to 1 when linear and 2 when exponential.
+	Multiplier float64 `json:"multiplier,omitempty"`
+	// MaxDelay caps the delay, unless it is 0.
+	MaxDelay time.Duration `json:"maxDelay,omitempty"`
 }
 
+// DelayForAttempt returns the delay before the nth retry, starting at 1.
+func (r Retry) DelayForAttempt(n int) time.Duration {
+	n = max(n, 1)
+
+	delay := r.Delay
+	switch r.Strategy {
+	case BackoffLinear:
+		multiplier := r.Multiplier
+		if multiplier == 0 {
+			multiplier = 1
+		}
+		delay = time.Duration(float64(r.Delay) * (1 + multiplier*float64(n-1)))
+	case BackoffExponential:
+		multiplier := r.Multiplier
+		if multiplier == 0 {
+			multiplier = 2
+		}
+		delay = time.Duration(float64(r.Delay) * math.Pow(multiplier, float64(n-1)))
+	}
+
+	if r.MaxDelay > 0 && (delay > r.MaxDelay || delay < 0) {
+		return r.MaxDelay
+	}
+	return delay
+}
+
+// Localization is a message in a single locale. Locale is a BCP 47 language
+// tag such as "en", "en-US" or "zh-Hant". Message may contain placeholders
+// such as "{count} items remaining", which are filled from Args.
 type Localization struct {
-	Locale  string // TODO: use https://www.rfc-editor.org/rfc/bcp/bcp47.txt
-	Message string
+	Locale  string            `json:"locale"`
+	Message string            `json:"message"`
+	Args    map[string]string `json:"args,omitempty"`
+}
+
+// NewLocalization returns a Localization with locale canonicalized as a BCP 47
+// tag, or an error if locale is not a well-formed tag.
+func NewLocalization(locale string, message string) (Localization, error) {
+	l := Localization{Locale: locale, Message: message}
+	tag, err := l.Tag()
+	if err != nil {
+		return Localization{}, err
+	}
+	l.Locale = tag.String()
+	return l, nil
+}
+
+// Tag parses Locale as a BCP 47 language tag.
+func (l Localization) Tag() (language.Tag, error) {
+	return language.Parse(l.Locale)
 }
 
 type Resource struct {
-	Type        string
-	Name        string
-	Owner       string
-	Description string
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Owner       string `json:"owner,omitempty"`
+	Description string `json:"description,omitempty"`
 }
 
 type Help struct {
-	Description string
-	URL         string
+	Description string `json:"description"`
+	URL         string `json:"url"`
 }
 
 type QuotaViolation struct {
-	Subject     string
-	Description string
+	Subject     string `json:"subject"`
+	Description string `json:"description"`
 }
 
 func (v QuotaViolation) LogValue() slog.Value {
@@ -39,9 +182,9 @@ func (v QuotaViolation) LogValue() slog.Value {
 }
 
 type PreconditionViolation struct {
-	Type        string
-	Subject     string
-	Description string
+	Type        string `json:"type"`
+	Subject     string `json:"subject"`
+	Description string `json:"description"`
 }
 
 func (v PreconditionViolation) LogValue() slog.Value {
@@ -53,8 +196,8 @@ func (v PreconditionViolation) LogValue() slog.Value {
 }
 
 type FieldViolation struct {
-	Field       string
-	Description string
+	Field       string `json:"field"`
+	Description string `json:"description"`
 }
 
 func (v FieldViolation) LogValue() slog.Value {
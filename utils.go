@@ -2,6 +2,8 @@ package errors
 
 import (
 	"errors"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/samber/lo"
@@ -14,32 +16,237 @@ func recursive(err *Error, tap func(*Error)) {
 
 	tap(err)
 
-	if err.err == nil {
-		return
-	}
-
-	var child *Error
-	if errors.As(err.err, &child) {
+	for _, child := range childErrors(err) {
 		recursive(child, tap)
 	}
 }
 
+// recursiveAttr resolves an attribute on the innermost *Error of the chain.
+// When the chain splits into several branches, such as with Join, the first
+// branch holding an *Error is followed, as errors.As does.
 func recursiveAttr[T any](err *Error, attr func(*Error) T) T {
 	if err == nil {
 		var zero T
 		return zero
 	}
 
-	if err.err == nil {
+	if children := childErrors(err); len(children) > 0 {
+		return recursiveAttr[T](children[0], attr)
+	}
+
+	return attr(err)
+}
+
+// recursiveSet is like recursiveAttr, but resolves the attribute on the
+// innermost layer where it is set, so that a value set on an outer layer is not
+// hidden by inner layers without one.
+func recursiveSet[T any](err *Error, attr func(*Error) *T) *T {
+	var value *T
+	for err != nil {
+		if v := attr(err); v != nil {
+			value = v
+		}
+		children := childErrors(err)
+		if len(children) == 0 {
+			break
+		}
+		err = children[0]
+	}
+	return value
+}
+
+// recursiveSlice is like recursiveAttr, but when the chain splits into several
+// branches it concatenates the values found in every branch.
+func recursiveSlice[T any](err *Error, attr func(*Error) []T) []T {
+	if err == nil {
+		return nil
+	}
+
+	children := childErrors(err)
+	switch len(children) {
+	case 0:
 		return attr(err)
+	case 1:
+		return recursiveSlice(children[0], attr)
 	}
 
+	var values []T
+	for _, child := range children {
+		values = append(values, recursiveSlice(child, attr)...)
+	}
+	return values
+}
+
+// childErrors returns the nearest *Error of every branch of the cause of err.
+// Causes implementing Unwrap() []error, like Join or the errors.Join and
+// fmt.Errorf with several %w of the standard library, have one branch per
+// error.
+func childErrors(err *Error) []*Error {
+	if err.err == nil {
+		return nil
+	}
+	return nearestErrors(err.err)
+}
+
+func nearestErrors(err error) []*Error {
+	switch x := err.(type) {
+	case *Error:
+		return []*Error{x}
+	case interface{ Unwrap() []error }:
+		var errs []*Error
+		for _, branch := range x.Unwrap() {
+			if branch != nil {
+				errs = append(errs, nearestErrors(branch)...)
+			}
+		}
+		return errs
+	case interface{ Unwrap() error }:
+		if next := x.Unwrap(); next != nil {
+			if errs := nearestErrors(next); len(errs) > 0 {
+				return errs
+			}
+		}
+	}
+
+	// Errors with an As method, like the adapter of WithCauseChain, can expose
+	// an *Error that Unwrap does not reach.
 	var child *Error
-	if errors.As(err.err, &child) {
-		return recursiveAttr[T](child, attr)
+	if errors.As(err, &child) {
+		return []*Error{child}
 	}
+	return nil
+}
 
-	return attr(err)
+// recursiveUniq gathers the values of every layer of the chain, from the
+// outermost, and drops the duplicates.
+func recursiveUniq[T comparable](err *Error, attr func(*Error) []T) []T {
+	var values []T
+	recursive(err, func(e *Error) {
+		values = append(values, attr(e)...)
+	})
+	return lo.Uniq(values)
+}
+
+// mapChain returns a copy of the chain of err with edit applied to a clone of
+// every *Error layer, including the branches of Join. Each copied layer matches
+// with Is the layer it was copied from. The wrappers of other
+// packages in between are replaced by a rewrapped holding the copy of their
+// cause. A cause that reaches an *Error only through an As method, such as the
+// Cause() chains of WithCauseChain, can't be rebuilt and is replaced by its
+// message, so that no layer escapes edit.
+func mapChain(err *Error, edit func(ErrorBuilder) ErrorBuilder) *Error {
+	if err == nil {
+		return nil
+	}
+	e2 := edit(err.clone())
+	e2.err = mapCause(err.err, edit)
+	e2.copyOf = err
+	return (*Error)(&e2)
+}
+
+func mapCause(err error, edit func(ErrorBuilder) ErrorBuilder) error {
+	if err == nil || len(nearestErrors(err)) == 0 {
+		return err
+	}
+
+	switch x := err.(type) {
+	case *Error:
+		return mapChain(x, edit)
+	case *joinError:
+		return &joinError{errs: mapCauses(x.errs, edit)}
+	case interface{ Unwrap() []error }:
+		return &rewrappedJoin{wrapper: err, causes: mapCauses(x.Unwrap(), edit)}
+	case interface{ Unwrap() error }:
+		if next := x.Unwrap(); len(nearestErrors(next)) > 0 {
+			return &rewrapped{wrapper: err, cause: mapCause(next, edit)}
+		}
+	}
+
+	return errors.New(err.Error())
+}
+
+func mapCauses(errs []error, edit func(ErrorBuilder) ErrorBuilder) []error {
+	mapped := make([]error, len(errs))
+	for i, err := range errs {
+		mapped[i] = mapCause(err, edit)
+	}
+	return mapped
+}
+
+// mapInnermost returns a copy of err with edit applied to a clone of its
+// innermost *Error, the layer recursiveAttr resolves. Only the layers on the way
+// to it are copied, each matching with Is the layer it was copied from; the
+// other branches are shared with err. When that way goes
+// through a cause mapChain can't rebuild, edit is applied to the deepest layer
+// before it instead.
+func mapInnermost(err *Error, edit func(ErrorBuilder) ErrorBuilder) *Error {
+	if err == nil {
+		return nil
+	}
+	e2 := err.clone()
+	e2.copyOf = err
+	if cause, ok := mapFirstCause(err.err, edit); ok {
+		e2.err = cause
+	} else {
+		e2 = edit(e2)
+	}
+	return (*Error)(&e2)
+}
+
+// mapFirstCause applies mapInnermost to the first *Error of err and reports
+// whether it could.
+func mapFirstCause(err error, edit func(ErrorBuilder) ErrorBuilder) (error, bool) {
+	if err == nil || len(nearestErrors(err)) == 0 {
+		return err, false
+	}
+
+	switch x := err.(type) {
+	case *Error:
+		return mapInnermost(x, edit), true
+	case *joinError:
+		errs, ok := mapFirstBranch(x.errs, edit)
+		return &joinError{errs: errs}, ok
+	case interface{ Unwrap() []error }:
+		errs, ok := mapFirstBranch(x.Unwrap(), edit)
+		return &rewrappedJoin{wrapper: err, causes: errs}, ok
+	case interface{ Unwrap() error }:
+		if cause, ok := mapFirstCause(x.Unwrap(), edit); ok {
+			return &rewrapped{wrapper: err, cause: cause}, true
+		}
+	}
+
+	return err, false
+}
+
+func mapFirstBranch(errs []error, edit func(ErrorBuilder) ErrorBuilder) ([]error, bool) {
+	for i, err := range errs {
+		if len(nearestErrors(err)) == 0 {
+			continue
+		}
+		cause, ok := mapFirstCause(err, edit)
+		mapped := slices.Clone(errs)
+		mapped[i] = cause
+		return mapped, ok
+	}
+	return errs, false
+}
+
+// cloneSlice copies s, or returns nil if it is empty, like the emptied slices of
+// a released builder.
+func cloneSlice[S ~[]E, E any](s S) S {
+	if len(s) == 0 {
+		return nil
+	}
+	return slices.Clone(s)
+}
+
+// cloneMap copies m, or returns nil if it is empty, like the cleared maps of a
+// released builder.
+func cloneMap[M ~map[K]V, K comparable, V any](m M) M {
+	if len(m) == 0 {
+		return nil
+	}
+	return maps.Clone(m)
 }
 
 func deepCopyPtr[T any](p *T) *T {
@@ -56,5 +263,67 @@ func coalesceOrEmpty[T comparable](v ...T) T {
 }
 
 func printTab(sb *strings.Builder) {
-	sb.WriteString("	")
+	sb.WriteString(getVerboseIndent())
+}
+
+// unwrapAll returns the direct causes of err, following both the single and the
+// multi-error Unwrap conventions.
+func unwrapAll(err error) []error {
+	switch u := err.(type) {
+	case interface{ Unwrap() []error }:
+		return lo.Compact(u.Unwrap())
+	case interface{ Unwrap() error }:
+		if cause := u.Unwrap(); cause != nil {
+			return []error{cause}
+		}
+	}
+	return nil
+}
+
+// groupFieldViolations groups the descriptions of the violations by field,
+// keeping the order in which the fields first appear.
+func groupFieldViolations(violations []FieldViolation) []lo.Tuple2[string, []string] {
+	var groups []lo.Tuple2[string, []string]
+	index := map[string]int{}
+
+	for _, violation := range violations {
+		i, ok := index[violation.Field]
+		if !ok {
+			i = len(groups)
+			index[violation.Field] = i
+			groups = append(groups, lo.T2[string, []string](violation.Field, nil))
+		}
+		groups[i].B = append(groups[i].B, violation.Description)
+	}
+
+	return groups
+}
+
+func countReasons(err error, counts map[string]int, visited map[*Error]bool) {
+	var reason *string
+
+	for err != nil {
+		if ee, ok := err.(*Error); ok {
+			if visited[ee] {
+				break
+			}
+			visited[ee] = true
+			if ee.reason != nil {
+				reason = ee.reason
+			}
+		}
+
+		children := unwrapAll(err)
+		if len(children) > 1 {
+			for _, child := range children {
+				countReasons(child, counts, visited)
+			}
+			break
+		}
+		err = lo.FirstOrEmpty(children)
+	}
+
+	if reason != nil {
+		counts[*reason]++
+	}
 }
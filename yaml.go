@@ -0,0 +1,62 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// MarshalYAML encodes the error as a map with the same shape as MarshalJSON,
+// for use with gopkg.in/yaml.v3. Unset fields are omitted.
+func (e *Error) MarshalYAML() (any, error) {
+	data, err := e.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	var v any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	return yamlValue(v), nil
+}
+
+// UnmarshalYAML decodes an error encoded by MarshalYAML.
+func (e *Error) UnmarshalYAML(unmarshal func(any) error) error {
+	var v any
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalJSON(data)
+}
+
+// yamlValue converts the JSON numbers to integers where possible, so that they
+// are not written in scientific notation.
+func yamlValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, child := range v {
+			v[key] = yamlValue(child)
+		}
+		return v
+	case []any:
+		for i, child := range v {
+			v[i] = yamlValue(child)
+		}
+		return v
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	default:
+		return v
+	}
+}
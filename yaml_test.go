@@ -0,0 +1,38 @@
+package errors_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	"github.com/notjustmoney/errors"
+)
+
+func TestYAML(t *testing.T) {
+	is := assert.New(t)
+
+	definition := `reason: ERROR_REASON_RATE_LIMITED
+domain: identity
+metadata:
+    limit: "100"
+quotaViolations:
+    - subject: user
+      description: too many requests
+retry:
+    delay: 30000000000
+`
+
+	err := &errors.Error{}
+	is.NoError(yaml.Unmarshal([]byte(definition), err))
+	is.Equal("ERROR_REASON_RATE_LIMITED", *err.Reason())
+	is.Equal("identity", *err.Domain())
+	is.Equal(map[string]string{"limit": "100"}, err.Metadata())
+	is.Equal([]errors.QuotaViolation{{Subject: "user", Description: "too many requests"}}, err.QuotaViolations())
+	is.Equal(30*time.Second, err.Retry().Delay)
+
+	data, marshalErr := yaml.Marshal(err)
+	is.NoError(marshalErr)
+	is.YAMLEq(definition, string(data))
+}